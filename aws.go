@@ -0,0 +1,344 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bits-and-blooms/bitset"
+)
+
+// awsMinYear and awsMaxYear are the first and the last year of Amazon EventBridge cron expressions
+const (
+	awsMinYear = 1970
+	awsMaxYear = 2199
+)
+
+var (
+	awsRatePattern    = regexp.MustCompile(`^rate\((\d+) (minutes?|hours?|days?)\)$`)
+	awsNearestPattern = regexp.MustCompile(`^(\d{1,2}|L)W$`)
+	awsNthPattern     = regexp.MustCompile(`^([1-7])#([1-5])$`)
+	awsLastPattern    = regexp.MustCompile(`^([1-7])?L$`)
+)
+
+// AWSCron is a schedule of an Amazon EventBridge cron expression like "cron(0 12 ? * MON-FRI *)".
+// The fields are minutes, hours, day of month, month, day of week from 1 (SUN) to 7 (SAT) and year,
+// evaluated in UTC.
+type AWSCron struct {
+	text    string
+	minutes *bitset.BitSet
+	hours   *bitset.BitSet
+	months  *bitset.BitSet
+	years   *bitset.BitSet
+
+	// daysOfMonth is nil for "?", lastDay is "L", nearest are the days of "15W" and 0 for "LW"
+	daysOfMonth *bitset.BitSet
+	lastDay     bool
+	nearest     []int
+	// daysOfWeek from 0 (SUN) is nil for "?", nth are "3#2" with n 2 and "5L" with n -1
+	daysOfWeek *bitset.BitSet
+	nth        []weekdayNum
+}
+
+// AWSRate is a schedule of an Amazon EventBridge rate expression like "rate(5 minutes)"
+type AWSRate struct {
+	text     string
+	interval time.Duration
+	// Start is the first fire, the rule fires every interval from it. The zero value is the Unix epoch.
+	Start time.Time
+}
+
+// ParseAWS parse the cron(...) or rate(...) expression of Amazon EventBridge, it returns *AWSCron or *AWSRate
+func ParseAWS(text string) (Schedule, error) {
+	text = strings.TrimSpace(text)
+	if m := awsRatePattern.FindStringSubmatch(text); m != nil {
+		value, err := strconv.Atoi(m[1])
+		// the unit is singular for 1 and plural otherwise
+		if err != nil || value < 1 || (value == 1) != !strings.HasSuffix(m[2], "s") {
+			return nil, fmt.Errorf("invalid rate expression \"%s\"", text)
+		}
+		unit := map[string]time.Duration{"minute": time.Minute, "hour": time.Hour, "day": 24 * time.Hour}
+		return &AWSRate{text: text, interval: time.Duration(value) * unit[strings.TrimSuffix(m[2], "s")]}, nil
+	}
+	if strings.HasPrefix(text, "cron(") && strings.HasSuffix(text, ")") {
+		return parseAWSCron(text)
+	}
+	return nil, fmt.Errorf("schedule expression must be cron(...) or rate(...) (found \"%s\")", text)
+}
+
+func parseAWSCron(text string) (*AWSCron, error) {
+	fields := strings.Fields(text[len("cron(") : len(text)-1])
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("cron expression must consist of 6 fields (found %d in \"%s\")", len(fields), text)
+	}
+	a := &AWSCron{
+		text:    text,
+		minutes: bitset.New(60),
+		hours:   bitset.New(24),
+		months:  bitset.New(12),
+		years:   bitset.New(awsMaxYear + 1),
+	}
+	// the errors of the shared parser refer to the whole expression
+	c := &CronExpr{expression: text}
+	if err := c.setNumberHits(a.minutes, fields[0], 0, 60); err != nil {
+		return nil, err
+	}
+	if err := c.setNumberHits(a.hours, fields[1], 0, 24); err != nil {
+		return nil, err
+	}
+	months := bitset.New(13)
+	if err := c.setNumberHits(months, replaceOrdinals(fields[3], "FOO,JAN,FEB,MAR,APR,MAY,JUN,JUL,AUG,SEP,OCT,NOV,DEC"), 1, 13); err != nil {
+		return nil, err
+	}
+	for m, ok := months.NextSet(1); ok; m, ok = months.NextSet(m + 1) {
+		a.months.Set(m - 1)
+	}
+	if err := c.setNumberHits(a.years, fields[5], awsMinYear, awsMaxYear+1); err != nil {
+		return nil, err
+	}
+
+	dom, dow := fields[2], fields[4]
+	if (dom == "?") == (dow == "?") {
+		return nil, fmt.Errorf("exactly one of day of month and day of week must be '?' in expression \"%s\"", text)
+	}
+	if dom != "?" {
+		return a, a.parseDaysOfMonth(c, dom)
+	}
+	return a, a.parseDaysOfWeek(c, dow)
+}
+
+func (a *AWSCron) parseDaysOfMonth(c *CronExpr, field string) error {
+	a.daysOfMonth = bitset.New(32)
+	for _, item := range strings.Split(field, ",") {
+		if item == "L" {
+			a.lastDay = true
+			continue
+		}
+		if m := awsNearestPattern.FindStringSubmatch(item); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			if m[1] != "L" && (day < 1 || day > 31) {
+				return fmt.Errorf("invalid weekday nearest to '%s' in expression \"%s\"", item, a.text)
+			}
+			a.nearest = append(a.nearest, day)
+			continue
+		}
+		if strings.ContainsAny(item, "LW#?") {
+			return fmt.Errorf("invalid day of month '%s' in expression \"%s\"", item, a.text)
+		}
+		if err := c.setNumberHits(a.daysOfMonth, item, 1, 32); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AWSCron) parseDaysOfWeek(c *CronExpr, field string) error {
+	a.daysOfWeek = bitset.New(7)
+	days := bitset.New(8)
+	field = replaceOrdinals(field, "FOO,SUN,MON,TUE,WED,THU,FRI,SAT")
+	for _, item := range strings.Split(field, ",") {
+		if m := awsNthPattern.FindStringSubmatch(item); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			n, _ := strconv.Atoi(m[2])
+			a.nth = append(a.nth, weekdayNum{n: n, day: time.Weekday(day - 1)})
+			continue
+		}
+		if m := awsLastPattern.FindStringSubmatch(item); m != nil {
+			// "L" alone is the last day of week, Saturday
+			if m[1] == "" {
+				days.Set(7)
+				continue
+			}
+			day, _ := strconv.Atoi(m[1])
+			a.nth = append(a.nth, weekdayNum{n: -1, day: time.Weekday(day - 1)})
+			continue
+		}
+		if strings.ContainsAny(item, "LW#?") {
+			return fmt.Errorf("invalid day of week '%s' in expression \"%s\"", item, a.text)
+		}
+		if err := c.setNumberHits(days, item, 1, 8); err != nil {
+			return err
+		}
+	}
+	for d, ok := days.NextSet(1); ok; d, ok = days.NextSet(d + 1) {
+		a.daysOfWeek.Set(d - 1)
+	}
+	return nil
+}
+
+// String return the expression as written
+func (a *AWSCron) String() string {
+	return a.text
+}
+
+// Next time after t in UTC, ErrExhausted is returned after 2199 or the last year of the expression
+func (a *AWSCron) Next(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	base = base.UTC()
+	day := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, time.UTC)
+	for day.Year() <= awsMaxYear {
+		switch {
+		case !a.years.Test(uint(day.Year())):
+			day = time.Date(day.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+		case !a.months.Test(uint(day.Month() - 1)):
+			day = time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		default:
+			if a.matchDay(day) {
+				for h, ok := a.hours.NextSet(0); ok; h, ok = a.hours.NextSet(h + 1) {
+					for m, ok := a.minutes.NextSet(0); ok; m, ok = a.minutes.NextSet(m + 1) {
+						if next := day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute); next.After(base) {
+							return next, nil
+						}
+					}
+				}
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+	return time.Time{}, ErrExhausted
+}
+
+// Prev time before t in UTC, ErrExhausted is returned before 1970 or the first year of the expression
+func (a *AWSCron) Prev(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	base = base.UTC()
+	day := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, time.UTC)
+	for day.Year() >= awsMinYear {
+		switch {
+		case day.Year() > awsMaxYear || !a.years.Test(uint(day.Year())):
+			day = time.Date(day.Year(), 1, 0, 0, 0, 0, 0, time.UTC)
+		case !a.months.Test(uint(day.Month() - 1)):
+			day = time.Date(day.Year(), day.Month(), 0, 0, 0, 0, 0, time.UTC)
+		default:
+			if a.matchDay(day) {
+				for h, ok := prevSet(a.hours, 23); ok; h, ok = prevSet(a.hours, h-1) {
+					for m, ok := prevSet(a.minutes, 59); ok; m, ok = prevSet(a.minutes, m-1) {
+						if prev := day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute); prev.Before(base) {
+							return prev, nil
+						}
+					}
+				}
+			}
+			day = day.AddDate(0, 0, -1)
+		}
+	}
+	return time.Time{}, ErrExhausted
+}
+
+func (a *AWSCron) matchDay(day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if a.daysOfMonth != nil {
+		if a.daysOfMonth.Test(uint(day.Day())) || a.lastDay && day.Day() == last {
+			return true
+		}
+		for _, n := range a.nearest {
+			if n == 0 {
+				n = last
+			}
+			if nearestWeekday(day.Year(), day.Month(), n) == day.Day() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if a.daysOfWeek.Test(uint(day.Weekday())) {
+		return true
+	}
+	for _, wd := range a.nth {
+		if wd.day != day.Weekday() {
+			continue
+		}
+		if wd.n > 0 && (day.Day()-1)/7+1 == wd.n || wd.n < 0 && day.Day()+7 > last {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestWeekday to the day in the month without crossing the month, 0 if the month is shorter
+func nearestWeekday(year int, month time.Month, day int) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		return 0
+	}
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// String return the expression as written
+func (r *AWSRate) String() string {
+	return r.text
+}
+
+// Interval between the fires
+func (r *AWSRate) Interval() time.Duration {
+	return r.interval
+}
+
+// Next fire after t, counted from Start
+func (r *AWSRate) Next(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	start := r.Start
+	if start.IsZero() {
+		start = time.Unix(0, 0).UTC()
+	}
+	if base.Before(start) {
+		return start, nil
+	}
+	n := base.Sub(start)/r.interval + 1
+	return start.Add(n * r.interval), nil
+}
+
+// Prev fire before t, counted from Start. ErrExhausted is returned before Start.
+func (r *AWSRate) Prev(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	start := r.Start
+	if start.IsZero() {
+		start = time.Unix(0, 0).UTC()
+	}
+	if !start.Before(base) {
+		return time.Time{}, ErrExhausted
+	}
+	n := (base.Sub(start) - 1) / r.interval
+	return start.Add(n * r.interval), nil
+}
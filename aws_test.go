@@ -0,0 +1,152 @@
+package gocronexpr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAWS(t *testing.T) {
+	from := time.Date(2023, 12, 31, 23, 59, 0, 0, time.UTC)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"cron(0 12 ? * MON-FRI *)", []string{"2024-01-01 12:00", "2024-01-02 12:00", "2024-01-03 12:00", "2024-01-04 12:00", "2024-01-05 12:00", "2024-01-08 12:00"}},
+		{"cron(0/15 8 1 * ? *)", []string{"2024-01-01 08:00", "2024-01-01 08:15", "2024-01-01 08:30", "2024-01-01 08:45", "2024-02-01 08:00"}},
+		{"cron(0 18 L * ? *)", []string{"2024-01-31 18:00", "2024-02-29 18:00", "2024-03-31 18:00"}},
+		{"cron(0 9 15W * ? *)", []string{"2024-01-15 09:00", "2024-02-15 09:00", "2024-03-15 09:00", "2024-04-15 09:00", "2024-05-15 09:00", "2024-06-14 09:00"}},
+		{"cron(0 9 1W,LW * ? *)", []string{"2024-01-01 09:00", "2024-01-31 09:00", "2024-02-01 09:00", "2024-02-29 09:00", "2024-03-01 09:00", "2024-03-29 09:00"}},
+		{"cron(0 10 ? * 3#2 *)", []string{"2024-01-09 10:00", "2024-02-13 10:00", "2024-03-12 10:00"}},
+		{"cron(0 10 ? * 6L 2024)", []string{"2024-01-26 10:00", "2024-02-23 10:00", "2024-03-29 10:00"}},
+		{"cron(30 23 ? JAN,DEC L 2024-2025)", []string{"2024-01-06 23:30", "2024-01-13 23:30", "2024-01-20 23:30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			s, err := ParseAWS(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.String() != tt.text {
+				t.Errorf("String() = %v, want %v", s.String(), tt.text)
+			}
+			if got := occurrences(t, s, from, len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+			// Prev walks the same fires back
+			base, _ := time.Parse("2006-01-02 15:04", tt.want[len(tt.want)-1])
+			for i := len(tt.want) - 2; i >= 0; i-- {
+				prev, err := s.Prev(&base)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := prev.Format("2006-01-02 15:04"); got != tt.want[i] {
+					t.Errorf("Prev(%v) = %v, want %v", base, got, tt.want[i])
+				}
+				base = prev
+			}
+		})
+	}
+}
+
+func TestAWSCron_Next_utc(t *testing.T) {
+	s, err := ParseAWS("cron(0 12 * * ? *)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2024, 1, 1, 19, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	next, err := s.Next(&base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC); !next.Equal(want) || next.Location() != time.UTC {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+
+	s, _ = ParseAWS("cron(0 0 1 1 ? 2024)")
+	base = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.Next(&base); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Next() error = %v, want %v", err, ErrExhausted)
+	}
+	base = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.Prev(&base); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Prev() error = %v, want %v", err, ErrExhausted)
+	}
+}
+
+func TestParseAWS_rate(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"rate(1 minute)", time.Minute},
+		{"rate(5 minutes)", 5 * time.Minute},
+		{"rate(1 hour)", time.Hour},
+		{"rate(12 hours)", 12 * time.Hour},
+		{"rate(7 days)", 7 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			s, err := ParseAWS(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.(*AWSRate).Interval(); got != tt.want {
+				t.Errorf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	r, _ := ParseAWS("rate(5 minutes)")
+	r.(*AWSRate).Start = time.Date(2024, 1, 1, 0, 2, 0, 0, time.UTC)
+	want := []string{"2024-01-01 00:02", "2024-01-01 00:07", "2024-01-01 00:12"}
+	if got := occurrences(t, r, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+	base := time.Date(2024, 1, 1, 0, 7, 0, 0, time.UTC)
+	if next, _ := r.Next(&base); !next.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("Next() = %v, want %v", next, base.Add(5*time.Minute))
+	}
+	if prev, _ := r.Prev(&base); !prev.Equal(base.Add(-5 * time.Minute)) {
+		t.Errorf("Prev() = %v, want %v", prev, base.Add(-5*time.Minute))
+	}
+	if prev, _ := r.Prev(&r.(*AWSRate).Start); !prev.IsZero() {
+		t.Errorf("Prev() = %v, want ErrExhausted before Start", prev)
+	}
+}
+
+func TestParseAWS_invalid(t *testing.T) {
+	tests := []string{
+		"0 12 * * ? *",
+		"cron(0 12 * * ?)",
+		"cron(0 12 * * * *)",
+		"cron(0 12 ? * ? *)",
+		"cron(0 12 1 * MON *)",
+		"cron(60 12 ? * MON *)",
+		"cron(0 24 ? * MON *)",
+		"cron(0 12 ? 13 MON *)",
+		"cron(0 12 ? * 0 *)",
+		"cron(0 12 ? * 8 *)",
+		"cron(0 12 ? * 3#6 *)",
+		"cron(0 12 ? * 1-3L *)",
+		"cron(0 12 32W * ? *)",
+		"cron(0 12 1-5W * ? *)",
+		"cron(0 12 1# * ? *)",
+		"cron(0 L ? * MON *)",
+		"cron(0 12 ? * MON 1969)",
+		"cron(0 12 ? * MON 2200)",
+		"rate(0 minutes)",
+		"rate(1 minutes)",
+		"rate(5 minute)",
+		"rate(5 seconds)",
+		"rate(-5 minutes)",
+	}
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			if s, err := ParseAWS(text); err == nil {
+				t.Errorf("ParseAWS() = %v, want error", s)
+			}
+		})
+	}
+}
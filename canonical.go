@@ -0,0 +1,100 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	monthNames   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// String return the original expression
+func (c *CronExpr) String() string {
+	return c.expression
+}
+
+// Canonical regenerate a minimal expression from the parsed fields, equivalent expressions have the same
+// canonical form, e.g. "0 0 * * * mon,tue,wed" and "0 0 * * * 1-3" are both "0 0 * * * 1-3".
+// Months and days of week are written as names like "JAN" and "MON-FRI" if names is true,
+// otherwise days of week are numbered as ParseOptions.Weekdays of the expression.
+func (c *CronExpr) Canonical(names bool) string {
+	number := strconv.Itoa
+	month := func(v int) string {
+		return strconv.Itoa(v + 1)
+	}
+	weekday := c.weekday(names)
+	if names {
+		month = func(v int) string {
+			return monthNames[v]
+		}
+	}
+
+	fields := []string{
+		formatField(analyze(c.seconds, 0, 59), 0, number, ""),
+		formatField(analyze(c.minutes, 0, 59), 0, number, ""),
+		formatField(analyze(c.hours, 0, 23), 0, number, ""),
+		formatField(analyze(c.daysOfMonth, 1, 31), 0, number, ""),
+		formatField(analyze(c.months, 0, 11), 0, month, ""),
+		// "1/2" of days of week would include 7 which is Sunday
+		formatField(analyze(c.daysOfWeek, 0, 6), 0, weekday, weekday(6)),
+	}
+	return strings.Join(fields, " ")
+}
+
+// weekday format a day of week from 0 (Sunday) as a name, or in the numbering of the expression
+func (c *CronExpr) weekday(names bool) func(int) string {
+	switch {
+	case names:
+		return func(v int) string {
+			return weekdayNames[v]
+		}
+	case c.options.Weekdays == QuartzWeekdays:
+		return func(v int) string {
+			return strconv.Itoa(v + 1)
+		}
+	}
+	return strconv.Itoa
+}
+
+// formatField write the pattern, base is where "*" starts when parsing the field.
+// A step from another value is written as "start/step", or "start-last/step" if last is not empty.
+func formatField(p fieldPattern, base int, name func(int) string, last string) string {
+	switch p.kind {
+	case patternAll:
+		return "*"
+	case patternStep:
+		step := strconv.Itoa(p.step)
+		if p.start == base {
+			return "*/" + step
+		}
+		if last != "" {
+			return name(p.start) + "-" + last + "/" + step
+		}
+		return name(p.start) + "/" + step
+	}
+	items := make([]string, len(p.spans))
+	for i, s := range p.spans {
+		if s.from == s.to {
+			items[i] = name(s.from)
+		} else {
+			items[i] = name(s.from) + "-" + name(s.to)
+		}
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,61 @@
+package gocronexpr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronExpr_Canonical(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		wantNames  string
+	}{
+		{"* * * * * *", "* * * * * *", "* * * * * *"},
+		{"0 0 * * * mon,tue,wed", "0 0 * * * 1-3", "0 0 * * * MON-WED"},
+		{"0 0 * * * 1-3", "0 0 * * * 1-3", "0 0 * * * MON-WED"},
+		{"0 0 * * * 3,1,2", "0 0 * * * 1-3", "0 0 * * * MON-WED"},
+		{"0 0/30 8-10 * * MON-FRI", "0 */30 8-10 * * 1-5", "0 */30 8-10 * * MON-FRI"},
+		{"0,30 0-59/30 8,9,10 * * 1,2,3,4,5", "*/30 */30 8-10 * * 1-5", "*/30 */30 8-10 * * MON-FRI"},
+		{"0 0 0 25 12 ?", "0 0 0 25 12 *", "0 0 0 25 DEC *"},
+		{"0 30 23 30 1/3 ?", "0 30 23 30 */3 *", "0 30 23 30 */3 *"},
+		{"0 0 0 1 2/3 *", "0 0 0 1 2/3 *", "0 0 0 1 FEB/3 *"},
+		{"0 0 0 */2 * *", "0 0 0 2/2 * *", "0 0 0 2/2 * *"},
+		{"0 0 0 1/2 * *", "0 0 0 1/2 * *", "0 0 0 1/2 * *"},
+		{"0 0 7 ? * 0,7", "0 0 7 * * 0", "0 0 7 * * SUN"},
+		{"0 0 7 ? * 1/2", "0 0 7 * * 0-1,3,5", "0 0 7 * * SUN-MON,WED,FRI"},
+		{"0 0 7 ? * 1-5/2", "0 0 7 * * 1-6/2", "0 0 7 * * MON-SAT/2"},
+		{"0 0 7 ? * */2", "0 0 7 * * */2", "0 0 7 * * */2"},
+		{"0 0 1/3 * * *", "0 0 1/3 * * *", "0 0 1/3 * * *"},
+		{"0 5-5 * * * *", "0 5 * * * *", "0 5 * * * *"},
+		{"0 0 6,19 * * *", "0 0 6,19 * * *", "0 0 6,19 * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, err := New(tt.expression, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.String(); got != tt.expression {
+				t.Errorf("CronExpr.String() = %v, want %v", got, tt.expression)
+			}
+			if got := c.Canonical(false); got != tt.want {
+				t.Errorf("CronExpr.Canonical(false) = %v, want %v", got, tt.want)
+			}
+			if got := c.Canonical(true); got != tt.wantNames {
+				t.Errorf("CronExpr.Canonical(true) = %v, want %v", got, tt.wantNames)
+			}
+			// the canonical form parses to the same schedule
+			for _, canonical := range []string{tt.want, tt.wantNames} {
+				parsed, err := New(canonical, time.UTC)
+				if err != nil {
+					t.Errorf("New(%q) error = %v", canonical, err)
+					continue
+				}
+				if got := parsed.Canonical(false); got != tt.want {
+					t.Errorf("New(%q).Canonical(false) = %v, want %v", canonical, got, tt.want)
+				}
+			}
+		})
+	}
+}
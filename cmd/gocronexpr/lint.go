@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/dongfg/gocronexpr"
+	"os"
+	"time"
+)
+
+// lint print the warnings of the expression, exit with status 1 if there is any
+func lint(args []string) {
+	if len(args) != 1 {
+		flag.Usage()
+	}
+	cronExpr, err := gocronexpr.New(args[0], time.Local)
+	if err != nil {
+		colorize(colorRed, fmt.Sprintf("Error: %+v", err))
+		os.Exit(1)
+	}
+	warnings := cronExpr.Lint()
+	if len(warnings) == 0 {
+		colorize(colorGreen, "No problems found")
+		return
+	}
+	for _, w := range warnings {
+		colorize(colorYellow, fmt.Sprintf("%s (%s): %s", w.Code, w.Field, w.Message))
+		if w.Fix != "" {
+			colorize(colorGreen, fmt.Sprintf("  fix: %s", w.Fix))
+		}
+	}
+	os.Exit(1)
+}
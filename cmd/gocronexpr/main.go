@@ -1,11 +1,13 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/dongfg/gocronexpr"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,10 +23,12 @@ const (
 func init() {
 	flag.Usage = func() {
 		fmt.Printf("NAME:\n  %s\n", "gocronexpr - Display the time of the next N runs base on cron expression")
-		fmt.Printf("USAGE:\n  %s\n", "gocronexpr <cron> [N]")
+		fmt.Printf("USAGE:\n  %s\n  %s\n", "gocronexpr <cron> [N]", "gocronexpr stats <cron> [DAYS]")
+		fmt.Printf("  %s\n", "gocronexpr lint <cron>")
 		fmt.Printf("OPTIONS:\n")
-		fmt.Printf("  %-8s%s\n", "<cron>", "6 fields cron expression")
+		fmt.Printf("  %-8s%s\n", "<cron>", "6 fields cron expression, iCalendar DTSTART and RRULE, or AWS cron() and rate()")
 		fmt.Printf("  %-8s%s\n", "[N]", "next number of runs, default 5")
+		fmt.Printf("  %-8s%s\n", "[DAYS]", "days from now to analyze, default 30")
 		os.Exit(0)
 	}
 }
@@ -47,7 +51,26 @@ func parse() (cron string, times int, err error) {
 	return cron, times, err
 }
 
+// newSchedule parse a cron expression, an iCalendar RRULE with DTSTART, or an AWS cron() or rate() expression
+func newSchedule(text string) (gocronexpr.Schedule, error) {
+	if strings.HasPrefix(text, "cron(") || strings.HasPrefix(text, "rate(") {
+		return gocronexpr.ParseAWS(text)
+	}
+	if strings.Contains(strings.ToUpper(text), "RRULE:") {
+		return gocronexpr.ParseRRule(text, time.Local)
+	}
+	return gocronexpr.New(text, time.Local)
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		stats(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		lint(os.Args[2:])
+		return
+	}
 	if len(os.Args) != 2 && len(os.Args) != 3 {
 		flag.Usage()
 	}
@@ -57,7 +80,7 @@ func main() {
 		flag.Usage()
 	}
 
-	cronExpr, err := gocronexpr.New(cron, time.Local)
+	schedule, err := newSchedule(cron)
 	if err != nil {
 		colorize(colorRed, fmt.Sprintf("Error: %+v", err))
 		return
@@ -65,7 +88,10 @@ func main() {
 
 	base := time.Now()
 	for i := 0; i < times; i++ {
-		nextTime, err := cronExpr.Next(&base)
+		nextTime, err := schedule.Next(&base)
+		if errors.Is(err, gocronexpr.ErrExhausted) {
+			return
+		}
 		if err != nil {
 			colorize(colorRed, err.Error())
 			return
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/dongfg/gocronexpr"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stats print the statistics of the fires in the next days
+func stats(args []string) {
+	if len(args) != 1 && len(args) != 2 {
+		flag.Usage()
+	}
+	days := 30
+	if len(args) == 2 {
+		d, err := strconv.Atoi(args[1])
+		if err != nil || d <= 0 {
+			colorize(colorRed, fmt.Sprintf("Error: invalid days %s", args[1]))
+			flag.Usage()
+		}
+		days = d
+	}
+
+	cronExpr, err := gocronexpr.New(args[0], time.Local)
+	if err != nil {
+		colorize(colorRed, fmt.Sprintf("Error: %+v", err))
+		return
+	}
+	from := time.Now().Truncate(time.Second)
+	s, err := cronExpr.Stats(from, from.AddDate(0, 0, days))
+	if err != nil {
+		colorize(colorRed, err.Error())
+		return
+	}
+
+	colorize(colorGreen, fmt.Sprintf("%-15s%s%d in %d days", "Runs:", colorYellow, s.Count, days))
+	if s.Count == 0 {
+		return
+	}
+	colorize(colorGreen, fmt.Sprintf("%-15s%s%.2f", "Per day:", colorYellow, s.Rate(24*time.Hour)))
+	colorize(colorGreen, fmt.Sprintf("%-15s%s%s", "First:", colorYellow, s.First.Format("2006-01-02 15:04:05")))
+	colorize(colorGreen, fmt.Sprintf("%-15s%s%s", "Last:", colorYellow, s.Last.Format("2006-01-02 15:04:05")))
+	if s.Count > 1 {
+		colorize(colorGreen, fmt.Sprintf("%-15s%s%s", "Min interval:", colorYellow, s.MinInterval))
+		colorize(colorGreen, fmt.Sprintf("%-15s%s%s", "Max interval:", colorYellow, s.MaxInterval))
+		colorize(colorGreen, fmt.Sprintf("%-15s%s%s", "Mean interval:", colorYellow, s.MeanInterval.Round(time.Second)))
+	}
+
+	colorize(colorGreen, "By hour:")
+	for hour, n := range s.ByHour {
+		if n > 0 {
+			colorize(colorGreen, fmt.Sprintf("  %02d  %s%6d %s", hour, colorYellow, n, bar(n, s.Count)))
+		}
+	}
+	colorize(colorGreen, "By weekday:")
+	for day, n := range s.ByWeekday {
+		if n > 0 {
+			colorize(colorGreen, fmt.Sprintf("  %s %s%6d %s", time.Weekday(day).String()[:3], colorYellow, n, bar(n, s.Count)))
+		}
+	}
+}
+
+// bar of n relative to the total, 40 characters at most
+func bar(n, total int) string {
+	return strings.Repeat("#", (n*40+total-1)/total)
+}
@@ -0,0 +1,514 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bits-and-blooms/bitset"
+)
+
+// Dialect of cron expressions
+type Dialect int
+
+const (
+	// Spring is the 6 fields format of this package, day of week 0-7 with Sunday 0 or 7
+	Spring Dialect = iota
+	// Quartz has seconds and an optional year, day of week 1-7 with Sunday 1 and '?' in one of the day fields
+	Quartz
+	// Vixie is the 5 fields format of crontab, a day matches either day field when both are restricted
+	Vixie
+	// Kubernetes is the 5 fields format of CronJob schedules, day of week 0-6
+	Kubernetes
+	// AWS is the cron() and rate() format of Amazon EventBridge, see ParseAWS
+	AWS
+)
+
+var dialectNames = [...]string{"Spring", "Quartz", "Vixie", "Kubernetes", "AWS"}
+
+func (d Dialect) String() string {
+	if d < Spring || d > AWS {
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+	return dialectNames[d]
+}
+
+var (
+	cronMacros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+	quartzLastDayPattern = regexp.MustCompile(`^L(-\d{1,2})?$`)
+)
+
+// Loss is a construct of the expression which another dialect can't express exactly
+type Loss struct {
+	// Field is the name of the field, e.g. "second", empty for the whole expression
+	Field string
+	// Value is the construct in the source expression
+	Value   string
+	Message string
+}
+
+func (l Loss) String() string {
+	if l.Field == "" {
+		return fmt.Sprintf("'%s': %s", l.Value, l.Message)
+	}
+	return fmt.Sprintf("%s '%s': %s", l.Field, l.Value, l.Message)
+}
+
+// ConvertError lists the constructs which the target dialect doesn't support, it wraps ErrNotRepresentable
+type ConvertError struct {
+	Expr        string
+	From        Dialect
+	To          Dialect
+	Unsupported []Loss
+}
+
+func (e *ConvertError) Error() string {
+	messages := make([]string, len(e.Unsupported))
+	for i, l := range e.Unsupported {
+		messages[i] = l.String()
+	}
+	return fmt.Sprintf("can't convert \"%s\" from %s to %s: %s", e.Expr, e.From, e.To, strings.Join(messages, "; "))
+}
+
+func (e *ConvertError) Unwrap() error {
+	return ErrNotRepresentable
+}
+
+// Convert the expression from a dialect to another, e.g. "0 0 9 * * 1-5" of Spring is "0 0 9 ? * 2-6" of Quartz.
+// Constructs converted with a different meaning are returned as losses, e.g. the seconds of "30 * * * * *" to Vixie.
+// Constructs the target dialect can't express, e.g. "L" of Quartz to Spring, fail with a *ConvertError.
+func Convert(expr string, from, to Dialect) (string, []Loss, error) {
+	if from < Spring || from > AWS || to < Spring || to > AWS {
+		return "", nil, fmt.Errorf("unknown dialect %s or %s", from, to)
+	}
+	v := &converter{expr: expr, from: from, to: to}
+	f, err := v.split()
+	if err != nil {
+		return "", nil, err
+	}
+	result, err := v.convert(f)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(v.unsupported) > 0 {
+		return "", nil, &ConvertError{Expr: expr, From: from, To: to, Unsupported: v.unsupported}
+	}
+	if from == to {
+		return expr, nil, nil
+	}
+
+	switch to {
+	case Spring:
+		_, err = New(result, time.UTC)
+	case AWS:
+		_, err = ParseAWS(result)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return result, v.losses, nil
+}
+
+// dialectFields are the fields of an expression in the source dialect, seconds and years default to "0" and "*"
+type dialectFields struct {
+	second     string
+	minute     string
+	hour       string
+	dayOfMonth string
+	month      string
+	dayOfWeek  string
+	year       string
+}
+
+type converter struct {
+	expr        string
+	from        Dialect
+	to          Dialect
+	losses      []Loss
+	unsupported []Loss
+}
+
+func (v *converter) lossy(field string, value string, format string, args ...interface{}) {
+	v.losses = append(v.losses, Loss{Field: field, Value: value, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *converter) unsupportedf(field string, value string, format string, args ...interface{}) {
+	v.unsupported = append(v.unsupported, Loss{Field: field, Value: value, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *converter) split() (dialectFields, error) {
+	f := dialectFields{second: "0", year: "*"}
+	text := strings.TrimSpace(v.expr)
+	var fields []string
+	switch v.from {
+	case Spring:
+		if _, err := New(text, time.UTC); err != nil {
+			return f, err
+		}
+		fields = strings.Fields(text)
+		f.second, fields = fields[0], fields[1:]
+	case Quartz:
+		fields = strings.Fields(text)
+		if len(fields) != 6 && len(fields) != 7 {
+			return f, fmt.Errorf("cron expression must consist of 6 or 7 fields (found %d in \"%s\")", len(fields), v.expr)
+		}
+		if len(fields) == 7 {
+			f.year = fields[6]
+		}
+		f.second, fields = fields[0], fields[1:6]
+	case Vixie, Kubernetes:
+		if strings.HasPrefix(text, "@") {
+			macro, ok := cronMacros[strings.ToLower(text)]
+			if !ok {
+				v.unsupportedf("", text, "only @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly can be converted")
+				return f, nil
+			}
+			text = macro
+		}
+		fields = strings.Fields(text)
+		if len(fields) != 5 {
+			return f, fmt.Errorf("cron expression must consist of 5 fields (found %d in \"%s\")", len(fields), v.expr)
+		}
+	case AWS:
+		s, err := ParseAWS(text)
+		if err != nil {
+			return f, err
+		}
+		if r, ok := s.(*AWSRate); ok {
+			return v.rate(r), nil
+		}
+		fields = strings.Fields(text[len("cron(") : len(text)-1])
+		f.year = fields[5]
+	}
+	f.minute, f.hour, f.dayOfMonth, f.month, f.dayOfWeek = fields[0], fields[1], fields[2], fields[3], fields[4]
+	return f, nil
+}
+
+// rate of AWS as fields, the interval must divide an hour or a day
+func (v *converter) rate(r *AWSRate) dialectFields {
+	f := dialectFields{second: "0", minute: "0", hour: "*", dayOfMonth: "*", month: "*", dayOfWeek: "?", year: "*"}
+	if v.to == AWS {
+		// kept as written
+		return f
+	}
+	d := r.Interval()
+	switch {
+	case d == time.Minute:
+		f.minute = "*"
+	case d < time.Hour && time.Hour%d == 0:
+		f.minute = "*/" + strconv.Itoa(int(d/time.Minute))
+	case d == time.Hour:
+	case d < 24*time.Hour && d%time.Hour == 0 && 24*time.Hour%d == 0:
+		f.hour = "*/" + strconv.Itoa(int(d/time.Hour))
+	case d == 24*time.Hour:
+		f.hour = "0"
+	default:
+		v.unsupportedf("", r.String(), "interval of %s doesn't divide an hour or a day", d)
+	}
+	v.lossy("", r.String(), "rate counts from the creation of the rule, cron fires at fixed times")
+	return f
+}
+
+func (v *converter) convert(f dialectFields) (string, error) {
+	if len(v.unsupported) > 0 {
+		return "", nil
+	}
+	seconds, err := v.set(v.from, f.second, "", 0, 60)
+	if err != nil {
+		return "", err
+	}
+	if _, err := v.set(v.from, f.minute, "", 0, 60); err != nil {
+		return "", err
+	}
+	if _, err := v.set(v.from, f.hour, "", 0, 24); err != nil {
+		return "", err
+	}
+	month, err := v.month(f.month)
+	if err != nil {
+		return "", err
+	}
+	dayOfMonth, dayOfWeek, err := v.days(f.dayOfMonth, f.dayOfWeek)
+	if err != nil {
+		return "", err
+	}
+	if _, err := v.set(v.from, f.year, "", 1970, awsMaxYear+1); err != nil {
+		return "", err
+	}
+
+	fields := []string{f.minute, f.hour, dayOfMonth, month, dayOfWeek}
+	if v.to == Spring || v.to == Quartz {
+		fields = append([]string{f.second}, fields...)
+	} else if seconds.Count() != 1 || !seconds.Test(0) {
+		v.lossy(fieldNames[0], f.second, "%s has no seconds, fires at second 0", v.to)
+	}
+	switch {
+	case v.to == AWS || v.to == Quartz && f.year != "*":
+		fields = append(fields, f.year)
+	case v.to != Quartz && f.year != "*":
+		v.lossy("year", f.year, "%s has no year, fires every year", v.to)
+	}
+	if v.to == AWS {
+		return "cron(" + strings.Join(fields, " ") + ")", nil
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// set of plain items like "1-5/2,SAT" in the dialect, names are numbered from their index in the list of names
+func (v *converter) set(d Dialect, field string, names string, min int, max int) (*bitset.BitSet, error) {
+	if d == Vixie && strings.IndexFunc(field, unicode.IsLetter) >= 0 && strings.ContainsAny(field, ",-/") {
+		return nil, fmt.Errorf("names can't be in ranges or lists of Vixie cron: '%s' in expression \"%s\"", field, v.expr)
+	}
+	if names != "" {
+		field = replaceOrdinals(field, names)
+	}
+	bits := bitset.New(uint(max))
+	c := &CronExpr{expression: v.expr}
+	return bits, c.setNumberHits(bits, field, min, max)
+}
+
+// weekdays of plain items in the numbering of the dialect, Sunday is 0
+func (v *converter) weekdays(d Dialect, field string) (*bitset.BitSet, error) {
+	switch d {
+	case Quartz, AWS:
+		bits, err := v.set(d, field, "FOO,SUN,MON,TUE,WED,THU,FRI,SAT", 1, 8)
+		if err != nil {
+			return nil, err
+		}
+		days := bitset.New(7)
+		for i, ok := bits.NextSet(1); ok; i, ok = bits.NextSet(i + 1) {
+			days.Set(i - 1)
+		}
+		return days, nil
+	case Kubernetes:
+		return v.set(d, field, "SUN,MON,TUE,WED,THU,FRI,SAT", 0, 7)
+	}
+	bits, err := v.set(d, field, "SUN,MON,TUE,WED,THU,FRI,SAT", 0, 8)
+	if err != nil {
+		return nil, err
+	}
+	if bits.Test(7) {
+		bits.Set(0)
+		bits.Clear(7)
+	}
+	return bits, nil
+}
+
+// month is kept as written unless the target dialect reads it differently
+func (v *converter) month(field string) (string, error) {
+	const names = "FOO,JAN,FEB,MAR,APR,MAY,JUN,JUL,AUG,SEP,OCT,NOV,DEC"
+	bits, err := v.set(v.from, field, names, 1, 13)
+	if err != nil {
+		return "", err
+	}
+	if target, err := v.set(v.to, field, names, 1, 13); err != nil || target.SymmetricDifferenceCardinality(bits) > 0 {
+		return formatField(analyze(bits, 1, 12), 1, strconv.Itoa, "12"), nil
+	}
+	return field, nil
+}
+
+// days converts the day of month and day of week fields, '?' goes to one of them for Quartz and AWS
+func (v *converter) days(dom string, dow string) (string, string, error) {
+	if (v.from == Quartz || v.from == AWS) && (dom == "?") == (dow == "?") {
+		return "", "", fmt.Errorf("exactly one of day of month and day of week must be '?' in expression \"%s\"", v.expr)
+	}
+	domOut, domAll, err := v.dayOfMonth(dom)
+	if err != nil {
+		return "", "", err
+	}
+	dowOut, dowAll, err := v.dayOfWeek(dow)
+	if err != nil {
+		return "", "", err
+	}
+
+	// either field matches when both are restricted
+	or := false
+	switch v.from {
+	case Vixie:
+		or = !strings.HasPrefix(dom, "*") && !strings.HasPrefix(dow, "*")
+	case Kubernetes:
+		or = !kubernetesStar(dom) && !kubernetesStar(dow)
+	}
+	if or && (domAll || dowAll) {
+		// one of the fields matches every day
+		domOut, dowOut, domAll, dowAll = "*", "*", true, true
+	}
+	restricted := !domAll && !dowAll
+
+	switch v.to {
+	case Quartz, AWS:
+		if restricted {
+			v.unsupportedf(fieldNames[5], dow, "%s can't restrict both day of month and day of week", v.to)
+		} else if dowAll {
+			dowOut = "?"
+		} else {
+			domOut = "?"
+		}
+	case Spring:
+		if restricted && or {
+			v.unsupportedf(fieldNames[5], dow, "%s matches both day of month and day of week, not either", v.to)
+		}
+	case Vixie, Kubernetes:
+		star := strings.HasPrefix(domOut, "*") || strings.HasPrefix(dowOut, "*")
+		if v.to == Kubernetes {
+			star = kubernetesStar(domOut) || kubernetesStar(dowOut)
+		}
+		if restricted && or && star {
+			v.unsupportedf(fieldNames[5], dow, "%s matches both day of month and day of week when one is a wildcard", v.to)
+		} else if restricted && !or && !star {
+			v.unsupportedf(fieldNames[5], dow, "%s matches either day of month or day of week when both are restricted", v.to)
+		}
+	}
+	return domOut, dowOut, nil
+}
+
+// kubernetesStar reports whether the field is a wildcard when matching days, "*/2" is not
+func kubernetesStar(field string) bool {
+	return field == "*" || field == "?" || field == "*/1"
+}
+
+// dayOfMonth is kept as written unless the target dialect reads it differently, "*" if it matches every day
+func (v *converter) dayOfMonth(field string) (string, bool, error) {
+	if field == "?" && v.from != Vixie {
+		return "*", true, nil
+	}
+	var plain []string
+	special := false
+	for _, item := range strings.Split(field, ",") {
+		if !quartzLastDayPattern.MatchString(item) && !awsNearestPattern.MatchString(item) {
+			plain = append(plain, item)
+			continue
+		}
+		if day, err := strconv.Atoi(strings.TrimSuffix(item, "W")); v.from != Quartz && v.from != AWS || err == nil && (day < 1 || day > 31) {
+			return "", false, fmt.Errorf("invalid day of month '%s' in expression \"%s\"", item, v.expr)
+		}
+		special = true
+
+		var construct string
+		switch {
+		case strings.HasSuffix(item, "W"):
+			construct = "nearest weekday"
+		case item == "L":
+			construct = "last day of month"
+		default:
+			construct = "offset from the last day of month"
+		}
+		if v.to != Quartz && (v.to != AWS || strings.HasPrefix(item, "L-")) {
+			v.unsupportedf(fieldNames[3], item, "%s is not supported by %s", construct, v.to)
+		}
+	}
+	if len(plain) == 0 {
+		return field, false, nil
+	}
+	text := strings.Join(plain, ",")
+	bits, err := v.daysOfMonth(v.from, text)
+	if err != nil {
+		return "", false, err
+	}
+	if !special && bits.Count() == 31 {
+		return "*", true, nil
+	}
+	if target, err := v.daysOfMonth(v.to, text); err != nil || target.SymmetricDifferenceCardinality(bits) > 0 {
+		base := 1
+		if v.to == Spring {
+			base = 0
+		}
+		text = formatField(analyze(bits, 1, 31), base, strconv.Itoa, "31")
+		for _, item := range strings.Split(field, ",") {
+			if quartzLastDayPattern.MatchString(item) || awsNearestPattern.MatchString(item) {
+				text += "," + item
+			}
+		}
+		return text, false, nil
+	}
+	return field, false, nil
+}
+
+// daysOfMonth of plain items in the dialect, steps of '*' like "*/2" count from 0 in Spring and from 1 in others
+func (v *converter) daysOfMonth(d Dialect, field string) (*bitset.BitSet, error) {
+	if d != Spring {
+		return v.set(d, field, "", 1, 32)
+	}
+	bits, err := v.set(d, field, "", 0, 32)
+	if err != nil {
+		return nil, err
+	}
+	bits.Clear(0)
+	return bits, nil
+}
+
+// dayOfWeek is kept as written unless the target dialect numbers the days differently, "*" if it matches every day
+func (v *converter) dayOfWeek(field string) (string, bool, error) {
+	if field == "?" && v.from != Vixie {
+		return "*", true, nil
+	}
+	var plain, special []string
+	for _, item := range strings.Split(field, ",") {
+		if item == "L" && (v.from == Quartz || v.from == AWS) {
+			// "L" alone is the last day of week
+			item = "SAT"
+		}
+		if !awsNthPattern.MatchString(item) && !awsLastPattern.MatchString(item) {
+			plain = append(plain, item)
+			continue
+		}
+		if v.from != Quartz && v.from != AWS {
+			return "", false, fmt.Errorf("invalid day of week '%s' in expression \"%s\"", item, v.expr)
+		}
+		special = append(special, item)
+		if v.to != Quartz && v.to != AWS {
+			construct := "nth day of week of the month"
+			if strings.HasSuffix(item, "L") {
+				construct = "last day of week of the month"
+			}
+			v.unsupportedf(fieldNames[5], item, "%s is not supported by %s", construct, v.to)
+		}
+	}
+	if len(plain) == 0 {
+		return field, false, nil
+	}
+
+	text := strings.Join(plain, ",")
+	bits, err := v.weekdays(v.from, text)
+	if err != nil {
+		return "", false, err
+	}
+	if len(special) == 0 && bits.Count() == 7 {
+		return "*", true, nil
+	}
+	if target, err := v.weekdays(v.to, text); err != nil || target.SymmetricDifferenceCardinality(bits) > 0 {
+		base := 0
+		if v.to == Quartz || v.to == AWS {
+			base = 1
+		}
+		weekday := func(d int) string {
+			return strconv.Itoa(d + base)
+		}
+		text = formatField(analyze(bits, 0, 6), 0, weekday, weekday(6))
+	}
+	return strings.Join(append([]string{text}, special...), ","), false, nil
+}
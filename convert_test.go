@@ -0,0 +1,174 @@
+package gocronexpr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		expr       string
+		from       Dialect
+		to         Dialect
+		want       string
+		wantLosses []string
+	}{
+		{"0 0 9 * * 1-5", Spring, Quartz, "0 0 9 ? * 2-6", nil},
+		{"0 0 9 * * MON-FRI", Spring, Quartz, "0 0 9 ? * MON-FRI", nil},
+		{"0 0 9 * * */2", Spring, Quartz, "0 0 9 ? * */2", nil},
+		// Sunday is 7 of "1/2" in Spring
+		{"0 0 9 * * 1/2", Spring, Quartz, "0 0 9 ? * 1-2,4,6", nil},
+		{"0 0 9 15 * *", Spring, AWS, "cron(0 9 15 * ? *)", nil},
+		{"0 0 9 * * *", Spring, Quartz, "0 0 9 * * ?", nil},
+		{"30 0 9 * * 7", Spring, Kubernetes, "0 9 * * 0", []string{"second '30': Kubernetes has no seconds, fires at second 0"}},
+		// steps of '*' count from day 0 in Spring and from day 1 in the others
+		{"0 0 0 */2 * *", Spring, Vixie, "0 0 2-31/2 * *", nil},
+		{"0 0 0 */2 * *", Spring, Quartz, "0 0 0 2-31/2 * ?", nil},
+		{"0 0 */2 * *", Vixie, Spring, "0 0 0 1-31/2 * *", nil},
+		{"0 0 0 */10 * ?", Quartz, AWS, "cron(0 0 */10 * ? *)", nil},
+		{"0 0 0 */2 * *", Spring, Kubernetes, "0 0 2-31/2 * *", nil},
+		{"0 0 0 1-31/2 * *", Spring, Kubernetes, "0 0 1-31/2 * *", nil},
+		{"0 0 9 ? * 2-6", Quartz, Spring, "0 0 9 * * 1-5", nil},
+		{"0 0 9 ? * MON-FRI", Quartz, Vixie, "0 9 * * 1-5", nil},
+		{"0 0 9 ? * MON-FRI", Quartz, Kubernetes, "0 9 * * MON-FRI", nil},
+		{"0 0 9 ? * L", Quartz, Spring, "0 0 9 * * SAT", nil},
+		{"0 0 12 L * ? 2025", Quartz, AWS, "cron(0 12 L * ? 2025)", nil},
+		{"0 0 12 ? * 6L", Quartz, AWS, "cron(0 12 ? * 6L *)", nil},
+		{"0 9 1-31 * MON", Vixie, Spring, "0 0 9 * * *", nil},
+		{"0 9 * JAN-MAR 1", Kubernetes, Vixie, "0 9 * 1-3 1", nil},
+		{"0 9 * * 1,2", Kubernetes, Vixie, "0 9 * * 1,2", nil},
+		{"0 9 1 * MON", Vixie, Kubernetes, "0 9 1 * MON", nil},
+		{"@weekly", Vixie, AWS, "cron(0 0 ? * 1 *)", nil},
+		{"@daily", Kubernetes, Quartz, "0 0 0 * * ?", nil},
+		{"cron(0 12 ? * MON-FRI *)", AWS, Spring, "0 0 12 * * MON-FRI", nil},
+		{"cron(0 12 ? * 2-6 2025)", AWS, Kubernetes, "0 12 * * 1-5", []string{"year '2025': Kubernetes has no year, fires every year"}},
+		{"rate(15 minutes)", AWS, Vixie, "*/15 * * * *", []string{"'rate(15 minutes)': rate counts from the creation of the rule, cron fires at fixed times"}},
+		{"rate(6 hours)", AWS, Quartz, "0 0 */6 * * ?", []string{"'rate(6 hours)': rate counts from the creation of the rule, cron fires at fixed times"}},
+		{"rate(1 day)", AWS, Spring, "0 0 0 * * *", []string{"'rate(1 day)': rate counts from the creation of the rule, cron fires at fixed times"}},
+		{"0 0 9 ? * 2-6", Quartz, Quartz, "0 0 9 ? * 2-6", nil},
+		{"rate(7 minutes)", AWS, AWS, "rate(7 minutes)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr+" "+tt.from.String()+" to "+tt.to.String(), func(t *testing.T) {
+			got, losses, err := Convert(tt.expr, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+			var messages []string
+			for _, l := range losses {
+				messages = append(messages, l.String())
+			}
+			if !reflect.DeepEqual(messages, tt.wantLosses) {
+				t.Errorf("Convert() losses = %v, want %v", messages, tt.wantLosses)
+			}
+		})
+	}
+}
+
+// The converted expression fires at the same times, AWS is evaluated apart from CronExpr
+func TestConvert_sameFires(t *testing.T) {
+	tests := []struct {
+		expr string
+		from Dialect
+	}{
+		{"0 0 0 */2 * *", Spring},
+		{"0 30 9 */10 * *", Spring},
+		{"0 0 */2 * *", Vixie},
+		{"0 0 0 */3 * ?", Quartz},
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			spring, _, err := Convert(tt.expr, tt.from, Spring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			aws, _, err := Convert(tt.expr, tt.from, AWS)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := New(spring, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, err := ParseAWS(aws)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := occurrences(t, c, from, 40), occurrences(t, a, from, 40); !reflect.DeepEqual(got, want) {
+				t.Errorf("Next() of %s = %v, Next() of %s = %v", spring, got, aws, want)
+			}
+		})
+	}
+}
+
+func TestConvert_unsupported(t *testing.T) {
+	tests := []struct {
+		expr string
+		from Dialect
+		to   Dialect
+		want string
+	}{
+		{"0 0 9 1 * MON", Spring, Vixie, "day of week 'MON': Vixie matches either day of month or day of week when both are restricted"},
+		// the days of month are even days, "2-31/2" is not a wildcard
+		{"0 0 9 */2 * MON", Spring, Vixie, "day of week 'MON': Vixie matches either day of month or day of week when both are restricted"},
+		{"0 0 9 */2 * MON", Spring, Kubernetes, "day of week 'MON': Kubernetes matches either day of month or day of week when both are restricted"},
+		{"0 0 9 1 * MON", Spring, Quartz, "day of week 'MON': Quartz can't restrict both day of month and day of week"},
+		{"0 9 1 * MON", Vixie, Spring, "day of week 'MON': Spring matches both day of month and day of week, not either"},
+		{"0 9 */2 * MON", Kubernetes, Vixie, "day of week 'MON': Vixie matches both day of month and day of week when one is a wildcard"},
+		{"@reboot", Vixie, Spring, "'@reboot': only @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly can be converted"},
+		{"0 0 12 L-3 * ?", Quartz, AWS, "day of month 'L-3': offset from the last day of month is not supported by AWS"},
+		{"0 0 12 15W,L * ?", Quartz, Spring, "day of month '15W': nearest weekday is not supported by Spring; day of month 'L': last day of month is not supported by Spring"},
+		{"0 0 12 ? * 6#3", Quartz, Kubernetes, "day of week '6#3': nth day of week of the month is not supported by Kubernetes"},
+		{"cron(0 12 ? * 6L *)", AWS, Vixie, "day of week '6L': last day of week of the month is not supported by Vixie"},
+		{"rate(7 minutes)", AWS, Vixie, "'rate(7 minutes)': interval of 7m0s doesn't divide an hour or a day"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr+" "+tt.from.String()+" to "+tt.to.String(), func(t *testing.T) {
+			_, _, err := Convert(tt.expr, tt.from, tt.to)
+			var convertErr *ConvertError
+			if !errors.As(err, &convertErr) || !errors.Is(err, ErrNotRepresentable) {
+				t.Fatalf("Convert() error = %v, want *ConvertError", err)
+			}
+			var messages []string
+			for _, l := range convertErr.Unsupported {
+				messages = append(messages, l.String())
+			}
+			if got := strings.Join(messages, "; "); got != tt.want {
+				t.Errorf("Convert() unsupported = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert_invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		from Dialect
+	}{
+		{"0 0 9 * *", Spring},
+		{"0 0 9 1 * 2", Quartz},
+		{"0 9 * * ?", Vixie},
+		{"0 0 12 ? * 0", Quartz},
+		{"0 9 * * 7", Kubernetes},
+		{"0 9 * * MON-FRI", Vixie},
+		{"0 9 L * *", Vixie},
+		{"0 9 * * 5L", Kubernetes},
+		{"0 0 12 32W * ?", Quartz},
+		{"cron(0 12 * * * *)", AWS},
+		{"0 0 9 ? * 1 1969", Quartz},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got, _, err := Convert(tt.expr, tt.from, Spring); err == nil || errors.Is(err, ErrNotRepresentable) {
+				t.Errorf("Convert() = %v, %v, want invalid expression", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crontab parses crontab files into entries of gocronexpr.CronExpr.
+//
+// Both the user format of "crontab -e" and the system format of /etc/crontab and /etc/cron.d with a user column
+// are supported, as well as comments, environment variables, CRON_TZ and macros like "@daily".
+// Like Vixie cron, a line restricting both day of month and day of week fires when either matches,
+// see Entry.DayOfWeek. Steps of days of month like "*/2" count from day 1 as in cron.
+package crontab
+
+import (
+	"fmt"
+	"github.com/dongfg/gocronexpr"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Format of the crontab file
+type Format int
+
+const (
+	// User format of "crontab -e", schedule followed by the command
+	User Format = iota
+	// System format of /etc/crontab and /etc/cron.d, schedule followed by the user and the command
+	System
+)
+
+// Options of Parse
+type Options struct {
+	Format Format
+	// Seconds means the schedules have 6 fields starting with seconds, otherwise 5 fields starting with minutes
+	Seconds bool
+	// Location of the schedules before any CRON_TZ, nil means time.Local
+	Location *time.Location
+}
+
+// Crontab is the parse result of a crontab file
+type Crontab struct {
+	Variables []Variable
+	Entries   []Entry
+}
+
+// Variable is an environment variable line like "MAILTO=admin"
+type Variable struct {
+	// Line number starting from 1
+	Line  int
+	Name  string
+	Value string
+}
+
+// Entry is a job line
+type Entry struct {
+	// Line number starting from 1
+	Line int
+	// Schedule as written, e.g. "*/5 * * * *" or "@daily"
+	Schedule string
+	// Expr of the schedule, nil for "@reboot"
+	Expr *gocronexpr.CronExpr
+	// DayOfWeek is set if the schedule restricts both day of month and day of week, e.g. "0 0 1,15 * MON".
+	// Cron fires on either, so Expr has only the days of month and DayOfWeek only the days of week.
+	// A field starting with '*' like "*/2" is not a restriction, as in Vixie cron.
+	DayOfWeek *gocronexpr.CronExpr
+	// User running the command, empty in the user format
+	User    string
+	Command string
+}
+
+// Next fire time of the entry after t, the earlier of Expr and DayOfWeek.
+// gocronexpr.ErrExhausted is returned for "@reboot".
+func (e Entry) Next(t *time.Time) (time.Time, error) {
+	if e.Expr == nil {
+		return time.Time{}, gocronexpr.ErrExhausted
+	}
+	next, err := e.Expr.Next(t)
+	if e.DayOfWeek == nil {
+		return next, err
+	}
+	other, otherErr := e.DayOfWeek.Next(t)
+	if err != nil || (otherErr == nil && other.Before(next)) {
+		return other, otherErr
+	}
+	return next, nil
+}
+
+// ParseError of a line
+type ParseError struct {
+	// Line number starting from 1
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// macros in 6 fields, "@reboot" has no schedule
+var macros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+	"@reboot":   "",
+}
+
+var variablePattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*?)\s*$`)
+
+// Parse the crontab file. Invalid lines are skipped and reported together as *ParseError joined in the error,
+// the valid lines are returned in any case.
+func Parse(r io.Reader, options Options) (*Crontab, error) {
+	d, err := ParseDocument(r, options)
+	return d.Crontab(), err
+}
+
+// parseLine return the variable or the entry of the line, both are nil for blank and comment lines.
+// location is updated by CRON_TZ.
+func parseLine(number int, text string, options Options, location **time.Location) (*Variable, *Entry, error) {
+	line := strings.TrimSpace(text)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil, nil
+	}
+	if m := variablePattern.FindStringSubmatch(line); m != nil {
+		v := Variable{Line: number, Name: m[1], Value: unquote(m[2])}
+		if v.Name == "CRON_TZ" {
+			l, err := time.LoadLocation(v.Value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid CRON_TZ: %w", err)
+			}
+			*location = l
+		}
+		return &v, nil, nil
+	}
+	entry, err := parseEntry(number, line, options, *location)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &entry, nil
+}
+
+func parseEntry(number int, line string, options Options, location *time.Location) (Entry, error) {
+	entry := Entry{Line: number}
+	n := 5
+	if options.Seconds {
+		n = 6
+	}
+	if strings.HasPrefix(line, "@") {
+		n = 1
+	}
+	if options.Format == System {
+		n++
+	}
+	fields, rest := splitFields(line, n)
+	if len(fields) < n || rest == "" {
+		return entry, fmt.Errorf("missing command in \"%s\"", line)
+	}
+	if options.Format == System {
+		entry.User = fields[n-1]
+		fields = fields[:n-1]
+	}
+	entry.Schedule, entry.Command = strings.Join(fields, " "), rest
+
+	expression := entry.Schedule
+	if len(fields) == 1 {
+		macro, ok := macros[strings.ToLower(expression)]
+		if !ok {
+			return entry, fmt.Errorf("unknown macro '%s'", expression)
+		}
+		if macro == "" {
+			return entry, nil
+		}
+		expression = macro
+	} else if !options.Seconds {
+		expression = "0 " + expression
+	}
+	fields = strings.Fields(expression)
+	either := restricts(fields[3]) && restricts(fields[5])
+	fields[3] = stepsFromOne(fields[3])
+	expression = strings.Join(fields, " ")
+	if either {
+		return entry, entry.parseEither(expression, location)
+	}
+	expr, err := gocronexpr.New(expression, location)
+	if err != nil {
+		return entry, err
+	}
+	entry.Expr = expr
+	return entry, nil
+}
+
+// restricts reports whether a day field restricts the days, Vixie cron ignores fields starting with '*'
+func restricts(field string) bool {
+	return !strings.HasPrefix(field, "*") && field != "?"
+}
+
+// stepsFromOne rewrite the steps of '*' in days of month like "*/2" to "1-31/2", cron counts them from day 1
+// while CronExpr counts them from day 0
+func stepsFromOne(field string) string {
+	items := strings.Split(field, ",")
+	for i, item := range items {
+		if strings.HasPrefix(item, "*/") {
+			items[i] = "1-31" + item[1:]
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// parseEither parse the days of month of the 6 fields expression to Expr and the days of week to DayOfWeek
+func (e *Entry) parseEither(expression string, location *time.Location) error {
+	fields := strings.Fields(expression)
+	dow := fields[5]
+	fields[5] = "*"
+	expr, err := gocronexpr.New(strings.Join(fields, " "), location)
+	if err != nil {
+		return err
+	}
+	fields[3], fields[5] = "*", dow
+	dayOfWeek, err := gocronexpr.New(strings.Join(fields, " "), location)
+	if err != nil {
+		return err
+	}
+	e.Expr, e.DayOfWeek = expr, dayOfWeek
+	return nil
+}
+
+// splitFields split at most n whitespace separated fields, rest is the remaining text with its inner spacing
+func splitFields(line string, n int) ([]string, string) {
+	var fields []string
+	rest := strings.TrimLeft(line, " \t")
+	for len(fields) < n && rest != "" {
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+	return fields, rest
+}
+
+// unquote the value in matching single or double quotes
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,188 @@
+package crontab
+
+import (
+	"errors"
+	"github.com/dongfg/gocronexpr"
+	"strings"
+	"testing"
+	"time"
+)
+
+const userCrontab = `# m h dom mon dow command
+SHELL=/bin/bash
+MAILTO = "ops@example.com"
+
+*/5 * * * * /usr/bin/backup --incremental   >/dev/null 2>&1
+0 9 * * MON-FRI echo "good morning" | mail -s hi %user
+@daily /usr/local/bin/rotate
+CRON_TZ=Asia/Shanghai
+30 2 1 * * /usr/local/bin/report
+@reboot /usr/local/bin/start
+`
+
+func TestParse(t *testing.T) {
+	tab, err := Parse(strings.NewReader(userCrontab), Options{Location: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVariables := []Variable{
+		{2, "SHELL", "/bin/bash"},
+		{3, "MAILTO", "ops@example.com"},
+		{8, "CRON_TZ", "Asia/Shanghai"},
+	}
+	if len(tab.Variables) != len(wantVariables) {
+		t.Fatalf("Parse() variables = %v, want %v", tab.Variables, wantVariables)
+	}
+	for i, v := range wantVariables {
+		if tab.Variables[i] != v {
+			t.Errorf("Parse() variable = %v, want %v", tab.Variables[i], v)
+		}
+	}
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	wantEntries := []struct {
+		line     int
+		schedule string
+		command  string
+		// next fire after 00:01 of 2020-01-01 in location
+		location *time.Location
+		next     string
+	}{
+		{5, "*/5 * * * *", "/usr/bin/backup --incremental   >/dev/null 2>&1", time.UTC, "2020-01-01T00:05:00Z"},
+		{6, "0 9 * * MON-FRI", `echo "good morning" | mail -s hi %user`, time.UTC, "2020-01-01T09:00:00Z"},
+		{7, "@daily", "/usr/local/bin/rotate", time.UTC, "2020-01-02T00:00:00Z"},
+		{9, "30 2 1 * *", "/usr/local/bin/report", shanghai, "2020-01-01T02:30:00+08:00"},
+		{10, "@reboot", "/usr/local/bin/start", nil, ""},
+	}
+	if len(tab.Entries) != len(wantEntries) {
+		t.Fatalf("Parse() entries = %v, want %d", tab.Entries, len(wantEntries))
+	}
+	for i, want := range wantEntries {
+		got := tab.Entries[i]
+		if got.Line != want.line || got.Schedule != want.schedule || got.Command != want.command || got.User != "" {
+			t.Errorf("Parse() entry = %+v, want %+v", got, want)
+		}
+		if want.next == "" {
+			if got.Expr != nil {
+				t.Errorf("Parse() entry %d Expr = %v, want nil", want.line, got.Expr)
+			}
+			continue
+		}
+		base := time.Date(2020, 1, 1, 0, 1, 0, 0, want.location)
+		next, err := got.Expr.Next(&base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.Format(time.RFC3339) != want.next {
+			t.Errorf("entry %d Next() = %v, want %v", want.line, next.Format(time.RFC3339), want.next)
+		}
+	}
+}
+
+func TestParse_system(t *testing.T) {
+	input := "17 *\t* * *\troot    cd / && run-parts --report /etc/cron.hourly\n" +
+		"@weekly www-data /usr/bin/cleanup\n"
+	tab, err := Parse(strings.NewReader(input), Options{Format: System})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tab.Entries) != 2 {
+		t.Fatalf("Parse() entries = %v", tab.Entries)
+	}
+	if e := tab.Entries[0]; e.User != "root" || e.Command != "cd / && run-parts --report /etc/cron.hourly" {
+		t.Errorf("Parse() entry = %+v", e)
+	}
+	if e := tab.Entries[1]; e.User != "www-data" || e.Schedule != "@weekly" || e.Command != "/usr/bin/cleanup" {
+		t.Errorf("Parse() entry = %+v", e)
+	}
+}
+
+func TestParse_seconds(t *testing.T) {
+	tab, err := Parse(strings.NewReader("*/30 * * * * * /bin/ping\n"), Options{Seconds: true, Location: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tab.Entries) != 1 || tab.Entries[0].Expr.String() != "*/30 * * * * *" || tab.Entries[0].Command != "/bin/ping" {
+		t.Errorf("Parse() entries = %+v", tab.Entries)
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	input := `MAILTO=root
+* * * * *
+0 0 31 4 * /bin/never
+CRON_TZ=Mars/Olympus
+@fortnightly /bin/x
+61 * * * * /bin/y
+0 0 * * * /bin/ok
+`
+	tab, err := Parse(strings.NewReader(input), Options{})
+	if len(tab.Entries) != 1 || tab.Entries[0].Line != 7 {
+		t.Errorf("Parse() entries = %+v, want line 7 only", tab.Entries)
+	}
+	var lines []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var parseErr *ParseError
+		if !errors.As(e, &parseErr) {
+			t.Fatalf("Parse() error = %v, want ParseError", e)
+		}
+		lines = append(lines, parseErr.Line)
+	}
+	if want := []int{2, 3, 4, 5, 6}; len(lines) != len(want) || lines[0] != 2 || lines[4] != 6 {
+		t.Errorf("Parse() error lines = %v, want %v", lines, want)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "line 2: missing command") {
+		t.Errorf("Parse() error = %v", msg)
+	}
+}
+
+func TestParse_eitherDay(t *testing.T) {
+	tests := []struct {
+		line string
+		// fires after 2024-01-01 00:00, a Monday
+		want []string
+		// whether DayOfWeek is set
+		either bool
+	}{
+		{"0 0 1,15 * 3 cmd", []string{"2024-01-03", "2024-01-10", "2024-01-15", "2024-01-17", "2024-01-24"}, true},
+		// days 1, 11, 21 and 31 which are Wednesdays
+		{"0 0 */10 * 3 cmd", []string{"2024-01-31", "2024-02-21", "2024-05-01", "2024-07-31", "2024-08-21"}, false},
+		{"0 0 */2 * * cmd", []string{"2024-01-03", "2024-01-05", "2024-01-07", "2024-01-09", "2024-01-11"}, false},
+		{"0 0 */15,10 * * cmd", []string{"2024-01-10", "2024-01-16", "2024-01-31", "2024-02-01", "2024-02-10"}, false},
+		{"0 0 13 * FRI cmd", []string{"2024-01-05", "2024-01-12", "2024-01-13", "2024-01-19", "2024-01-26"}, true},
+		{"0 0 1 * * cmd", []string{"2024-02-01", "2024-03-01", "2024-04-01", "2024-05-01", "2024-06-01"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			tab, err := Parse(strings.NewReader(tt.line), Options{Location: time.UTC})
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry := tab.Entries[0]
+			if (entry.DayOfWeek != nil) != tt.either {
+				t.Errorf("Entry.DayOfWeek = %v, want set %v", entry.DayOfWeek, tt.either)
+			}
+			base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			var got []string
+			for len(got) < len(tt.want) {
+				next, err := entry.Next(&base)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, next.Format("2006-01-02"))
+				base = next
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Entry.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	tab, _ := Parse(strings.NewReader("@reboot cmd"), Options{})
+	if _, err := tab.Entries[0].Next(nil); !errors.Is(err, gocronexpr.ErrExhausted) {
+		t.Errorf("Entry.Next() error = %v, want %v", err, gocronexpr.ErrExhausted)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crontab
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Document is an editable crontab file, the lines not edited are written back byte-for-byte
+type Document struct {
+	options Options
+	lines   []line
+	// newline of the file, "\n" or "\r\n"
+	newline string
+}
+
+// line of the document, at most one of variable and entry is set
+type line struct {
+	// text including the line ending
+	text     string
+	variable *Variable
+	entry    *Entry
+}
+
+// ParseDocument parse the crontab file for editing, invalid lines are kept as they are and reported like Parse
+func ParseDocument(r io.Reader, options Options) (*Document, error) {
+	d := &Document{options: options, newline: "\n"}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return d, err
+	}
+
+	text := string(data)
+	if i := strings.IndexByte(text, '\n'); i > 0 && text[i-1] == '\r' {
+		d.newline = "\r\n"
+	}
+	location := d.location(0)
+	var errs []error
+	for text != "" {
+		end := strings.IndexByte(text, '\n') + 1
+		if end == 0 {
+			end = len(text)
+		}
+		l := line{text: text[:end]}
+		text = text[end:]
+
+		number := len(d.lines) + 1
+		content := strings.TrimRight(l.text, "\r\n")
+		l.variable, l.entry, err = parseLine(number, content, options, &location)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: number, Text: content, Err: err})
+		}
+		d.lines = append(d.lines, l)
+	}
+	return d, errors.Join(errs...)
+}
+
+// Crontab of the valid lines, line numbers are the current ones after editing
+func (d *Document) Crontab() *Crontab {
+	tab := &Crontab{}
+	for i, l := range d.lines {
+		if l.variable != nil {
+			v := *l.variable
+			v.Line = i + 1
+			tab.Variables = append(tab.Variables, v)
+		}
+		if l.entry != nil {
+			e := *l.entry
+			e.Line = i + 1
+			tab.Entries = append(tab.Entries, e)
+		}
+	}
+	return tab
+}
+
+// Add append the entry to the end of the document, its Line is ignored
+func (d *Document) Add(entry Entry) error {
+	l, err := d.format(len(d.lines), entry)
+	if err != nil {
+		return err
+	}
+	if n := len(d.lines); n > 0 && !strings.HasSuffix(d.lines[n-1].text, "\n") {
+		d.lines[n-1].text += d.newline
+	}
+	d.lines = append(d.lines, l)
+	return nil
+}
+
+// Update replace the entry at the line number, the line ending is kept
+func (d *Document) Update(number int, entry Entry) error {
+	i, err := d.entryIndex(number)
+	if err != nil {
+		return err
+	}
+	l, err := d.format(i, entry)
+	if err != nil {
+		return err
+	}
+	l.text = strings.TrimSuffix(l.text, d.newline) + lineEnding(d.lines[i].text)
+	d.lines[i] = l
+	return nil
+}
+
+// Remove the entry at the line number, the following lines move up
+func (d *Document) Remove(number int) error {
+	i, err := d.entryIndex(number)
+	if err != nil {
+		return err
+	}
+	d.lines = append(d.lines[:i], d.lines[i+1:]...)
+	return nil
+}
+
+// WriteTo implements io.WriterTo
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, d.String())
+	return int64(n), err
+}
+
+func (d *Document) String() string {
+	var b strings.Builder
+	for _, l := range d.lines {
+		b.WriteString(l.text)
+	}
+	return b.String()
+}
+
+// entryIndex of the line number, error if it's not an entry
+func (d *Document) entryIndex(number int) (int, error) {
+	if number < 1 || number > len(d.lines) {
+		return 0, fmt.Errorf("line %d out of range [1, %d]", number, len(d.lines))
+	}
+	if d.lines[number-1].entry == nil {
+		return 0, fmt.Errorf("line %d is not an entry", number)
+	}
+	return number - 1, nil
+}
+
+// format the entry as the i-th line, it's parsed back in the CRON_TZ of the line
+func (d *Document) format(i int, entry Entry) (line, error) {
+	if strings.ContainsAny(entry.Command, "\r\n") || strings.TrimSpace(entry.Command) == "" {
+		return line{}, fmt.Errorf("invalid command %q", entry.Command)
+	}
+	fields := []string{entry.Schedule}
+	if d.options.Format == System {
+		if entry.User == "" || strings.ContainsAny(entry.User, " \t") {
+			return line{}, fmt.Errorf("invalid user %q", entry.User)
+		}
+		fields = append(fields, entry.User)
+	}
+	text := strings.Join(append(fields, entry.Command), " ")
+
+	location := d.location(i)
+	variable, parsed, err := parseLine(i+1, text, d.options, &location)
+	if err != nil {
+		return line{}, err
+	}
+	if parsed == nil || variable != nil {
+		return line{}, fmt.Errorf("invalid entry %q", text)
+	}
+	return line{text: text + d.newline, entry: parsed}, nil
+}
+
+// location in effect before the i-th line
+func (d *Document) location(i int) *time.Location {
+	location := d.options.Location
+	if location == nil {
+		location = time.Local
+	}
+	for _, l := range d.lines[:i] {
+		if l.variable != nil && l.variable.Name == "CRON_TZ" {
+			if tz, err := time.LoadLocation(l.variable.Value); err == nil {
+				location = tz
+			}
+		}
+	}
+	return location
+}
+
+// lineEnding of the text, empty for the last line without one
+func lineEnding(text string) string {
+	if strings.HasSuffix(text, "\r\n") {
+		return "\r\n"
+	}
+	if strings.HasSuffix(text, "\n") {
+		return "\n"
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package crontab
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_roundTrip(t *testing.T) {
+	inputs := []string{
+		userCrontab,
+		"# no newline at the end\n*/5 * * * *   /bin/a",
+		"MAILTO=root\r\n0 0 * * *\t/bin/b\r\n\r\n",
+		"not a valid line\n0 0 * * * /bin/c\n",
+		"",
+	}
+	for _, input := range inputs {
+		d, _ := ParseDocument(strings.NewReader(input), Options{})
+		if got := d.String(); got != input {
+			t.Errorf("Document.String() = %q, want %q", got, input)
+		}
+	}
+}
+
+func TestDocument_edit(t *testing.T) {
+	input := "# backups\r\n" +
+		"SHELL=/bin/bash\r\n" +
+		"*/5  *  * * *   /usr/bin/backup   --incremental\r\n" +
+		"0 9 * * MON-FRI  /bin/morning # keep spacing\r\n" +
+		"CRON_TZ=Asia/Shanghai\r\n" +
+		"30 2 1 * * /bin/report"
+	d, err := ParseDocument(strings.NewReader(input), Options{Location: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Update(3, Entry{Schedule: "*/10 * * * *", Command: "/usr/bin/backup --full"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update(6, Entry{Schedule: "0 3 1 * *", Command: "/bin/report --monthly"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Remove(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Add(Entry{Schedule: "@hourly", Command: "/bin/ping"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# backups\r\n" +
+		"SHELL=/bin/bash\r\n" +
+		"*/10 * * * * /usr/bin/backup --full\r\n" +
+		"CRON_TZ=Asia/Shanghai\r\n" +
+		"0 3 1 * * /bin/report --monthly\r\n" +
+		"@hourly /bin/ping\r\n"
+	if got := d.String(); got != want {
+		t.Errorf("Document.String() = %q, want %q", got, want)
+	}
+
+	var b strings.Builder
+	if n, err := d.WriteTo(&b); err != nil || n != int64(len(want)) || b.String() != want {
+		t.Errorf("Document.WriteTo() = %d, %v", n, err)
+	}
+
+	entries := d.Crontab().Entries
+	if len(entries) != 3 || entries[1].Line != 5 || entries[2].Line != 6 {
+		t.Fatalf("Document.Crontab() entries = %+v", entries)
+	}
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, shanghai)
+	if next, _ := entries[1].Expr.Next(&base); next.Format(time.RFC3339) != "2020-01-01T03:00:00+08:00" {
+		t.Errorf("Next() = %v, want in CRON_TZ", next.Format(time.RFC3339))
+	}
+}
+
+func TestDocument_editErrors(t *testing.T) {
+	d, err := ParseDocument(strings.NewReader("MAILTO=root\n0 0 * * * root /bin/a\n"), Options{Format: System})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		edit func() error
+	}{
+		{"update variable", func() error { return d.Update(1, Entry{Schedule: "@daily", User: "root", Command: "/bin/b"}) }},
+		{"update out of range", func() error { return d.Update(3, Entry{Schedule: "@daily", User: "root", Command: "/bin/b"}) }},
+		{"remove variable", func() error { return d.Remove(1) }},
+		{"invalid schedule", func() error { return d.Add(Entry{Schedule: "0 0 31 4 *", User: "root", Command: "/bin/b"}) }},
+		{"missing user", func() error { return d.Add(Entry{Schedule: "@daily", Command: "/bin/b"}) }},
+		{"multiline command", func() error {
+			return d.Add(Entry{Schedule: "@daily", User: "root", Command: "/bin/b\n* * * * * root /bin/c"})
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.edit(); err == nil {
+			t.Errorf("%s: error = nil", tt.name)
+		}
+	}
+	if got := d.String(); got != "MAILTO=root\n0 0 * * * root /bin/a\n" {
+		t.Errorf("Document.String() = %q, want unchanged", got)
+	}
+}
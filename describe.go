@@ -0,0 +1,214 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Describe the expression in English, e.g. "0 0/30 8-10 * * MON-FRI" is
+// "Every 30 minutes, between 08:00 and 10:59, Monday through Friday"
+func (c *CronExpr) Describe() string {
+	return c.DescribeLocale(English)
+}
+
+// DescribeLocale describe the expression in the given locale
+func (c *CronExpr) DescribeLocale(l Locale) string {
+	d := describer{locale: l}
+	seconds := analyze(c.seconds, 0, 59)
+	minutes := analyze(c.minutes, 0, 59)
+	hours := analyze(c.hours, 0, 23)
+
+	parts := d.describeTime(seconds, minutes, hours)
+	parts = append(parts, d.describeDaysOfMonth(analyze(c.daysOfMonth, 1, 31))...)
+	parts = append(parts, d.describeDaysOfWeek(analyze(c.daysOfWeek, 0, 6))...)
+	parts = append(parts, d.describeMonths(analyze(c.months, 0, 11))...)
+	return l.Sentence(parts)
+}
+
+type describer struct {
+	locale Locale
+}
+
+// msg format the message for count n
+func (d describer) msg(id Message, n int, args ...interface{}) string {
+	return fmt.Sprintf(d.locale.Message(id, n), args...)
+}
+
+func (d describer) describeTime(seconds, minutes, hours fieldPattern) []string {
+	second, secondSingle := seconds.single()
+	minute, minuteSingle := minutes.single()
+	// fixed times of day, "At 06:00 and 19:00"
+	if hourList, ok := hours.singles(); ok && secondSingle && minuteSingle {
+		times := make([]string, len(hourList))
+		for i, hour := range hourList {
+			times[i] = d.locale.Time(hour, minute, second)
+		}
+		return []string{d.msg(MsgAtTimes, len(times), d.locale.List(times))}
+	}
+	hour, hourSingle := hours.single()
+	// "Every minute between 14:00 and 14:05"
+	if secondSingle && second == 0 && minutes.isRange() && hourSingle {
+		return []string{d.msg(MsgEveryMinuteBetween, 2,
+			d.locale.Time(hour, minutes.spans[0].from, 0), d.locale.Time(hour, minutes.spans[0].to, 0))}
+	}
+
+	secondZero := secondSingle && second == 0
+	minuteZero := secondZero && minuteSingle && minute == 0
+	var parts []string
+	if !secondZero {
+		parts = append(parts, d.describeUnit(seconds, secondMessages)...)
+	}
+	if !minuteZero && !(minutes.kind == patternAll && !secondZero) {
+		parts = append(parts, d.describeUnit(minutes, minuteMessages)...)
+	}
+	switch {
+	case hours.kind == patternAll:
+		if minuteZero {
+			parts = append(parts, d.msg(MsgEveryHour, 1))
+		}
+	case hours.kind == patternStep:
+		parts = append(parts, d.msg(MsgEveryNHours, hours.step, hours.step))
+		if hours.start != 0 {
+			parts = append(parts, d.msg(MsgStartingAtTime, 1, d.locale.Time(hours.start, 0, 0)))
+		}
+	case hours.isRange() || hourSingle:
+		from, to := hours.spans[0].from, hours.spans[0].to
+		if minuteZero {
+			parts = append(parts, d.msg(MsgEveryHourBetween, 2, d.locale.Time(from, 0, 0), d.locale.Time(to, 0, 0)))
+		} else {
+			parts = append(parts, d.msg(MsgBetweenTimes, 2, d.locale.Time(from, 0, 0), d.locale.Time(to, 59, 0)))
+		}
+	default:
+		id := MsgDuringHours
+		if minuteZero {
+			id = MsgEveryHourDuringHours
+		}
+		parts = append(parts, d.msg(id, len(hours.spans), d.describeSpans(hours.spans, strconv.Itoa)))
+	}
+	return parts
+}
+
+// unitMessages of seconds or minutes
+type unitMessages struct {
+	every, everyN, startingAt, between, at, atList Message
+}
+
+var (
+	secondMessages = unitMessages{MsgEverySecond, MsgEveryNSeconds, MsgStartingAtSecond, MsgSecondsBetween,
+		MsgAtSecond, MsgAtSeconds}
+	minuteMessages = unitMessages{MsgEveryMinute, MsgEveryNMinutes, MsgStartingAtMinute, MsgMinutesBetween,
+		MsgAtMinute, MsgAtMinutes}
+)
+
+// describeUnit describe seconds or minutes
+func (d describer) describeUnit(p fieldPattern, messages unitMessages) []string {
+	switch {
+	case p.kind == patternAll:
+		return []string{d.msg(messages.every, 1)}
+	case p.kind == patternStep:
+		parts := []string{d.msg(messages.everyN, p.step, p.step)}
+		if p.start != 0 {
+			parts = append(parts, d.msg(messages.startingAt, p.start, p.start))
+		}
+		return parts
+	case p.isRange():
+		return []string{d.msg(messages.between, 2, p.spans[0].from, p.spans[0].to)}
+	}
+	if v, ok := p.single(); ok {
+		return []string{d.msg(messages.at, v, v)}
+	}
+	return []string{d.msg(messages.atList, len(p.spans), d.describeSpans(p.spans, strconv.Itoa))}
+}
+
+func (d describer) describeDaysOfMonth(p fieldPattern) []string {
+	switch {
+	case p.kind == patternAll:
+		return nil
+	case p.kind == patternStep:
+		parts := []string{d.msg(MsgEveryNDays, p.step, p.step)}
+		if p.start != 1 {
+			parts = append(parts, d.msg(MsgStartingOnDay, p.start, p.start))
+		}
+		return parts
+	case p.isRange():
+		return []string{d.msg(MsgDaysBetween, 2, p.spans[0].from, p.spans[0].to)}
+	}
+	if v, ok := p.single(); ok {
+		return []string{d.msg(MsgOnDay, v, v)}
+	}
+	return []string{d.msg(MsgOnDays, len(p.spans), d.describeSpans(p.spans, strconv.Itoa))}
+}
+
+func (d describer) describeDaysOfWeek(p fieldPattern) []string {
+	name := func(day int) string {
+		return d.locale.Weekday(time.Weekday(day))
+	}
+	switch {
+	case p.kind == patternAll:
+		return nil
+	case p.kind == patternStep:
+		p = expandStep(p, 6)
+	case p.isRange():
+		return []string{d.msg(MsgWeekdaysBetween, 2, name(p.spans[0].from), name(p.spans[0].to))}
+	}
+	return []string{d.msg(MsgOnWeekdays, len(p.spans), d.describeSpans(p.spans, name))}
+}
+
+func (d describer) describeMonths(p fieldPattern) []string {
+	name := func(month int) string {
+		return d.locale.Month(time.Month(month + 1))
+	}
+	switch {
+	case p.kind == patternAll:
+		return nil
+	case p.kind == patternStep:
+		parts := []string{d.msg(MsgEveryNMonths, p.step, p.step)}
+		if p.start != 0 {
+			parts = append(parts, d.msg(MsgStartingInMonth, 1, name(p.start)))
+		}
+		return parts
+	case p.isRange():
+		return []string{d.msg(MsgMonthsBetween, 2, name(p.spans[0].from), name(p.spans[0].to))}
+	}
+	return []string{d.msg(MsgInMonths, len(p.spans), d.describeSpans(p.spans, name))}
+}
+
+// describeSpans "1, 3 through 5 and 7", a range of two values is written as two items
+func (d describer) describeSpans(spans []span, name func(int) string) string {
+	var items []string
+	for _, s := range spans {
+		switch s.to - s.from {
+		case 0:
+			items = append(items, name(s.from))
+		case 1:
+			items = append(items, name(s.from), name(s.to))
+		default:
+			items = append(items, d.msg(MsgSpan, 2, name(s.from), name(s.to)))
+		}
+	}
+	return d.locale.List(items)
+}
+
+// expandStep turn a step pattern into a list of single values
+func expandStep(p fieldPattern, max int) fieldPattern {
+	list := fieldPattern{kind: patternList}
+	for v := p.start; v <= max; v += p.step {
+		list.spans = append(list.spans, span{v, v})
+	}
+	return list
+}
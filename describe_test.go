@@ -0,0 +1,117 @@
+package gocronexpr
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// testGolden compare describe of each expression in the golden file, lines are "expression<TAB>description"
+func testGolden(t *testing.T, path string, describe func(c *CronExpr) string) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var updated []string
+	for i, line := range lines {
+		if line == "" || strings.HasPrefix(line, "#") {
+			updated = append(updated, line)
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 2)
+		c, err := New(fields[0], time.UTC)
+		if err != nil {
+			t.Errorf("%s:%d: New(%q) error = %v", path, i+1, fields[0], err)
+			continue
+		}
+		got := describe(c)
+		updated = append(updated, fields[0]+"\t"+got)
+		if *update {
+			continue
+		}
+		if len(fields) != 2 || got != fields[1] {
+			t.Errorf("%s:%d: describe(%q) = %q, want %q", path, i+1, fields[0], got, fields[len(fields)-1])
+		}
+	}
+
+	if *update {
+		if err := os.WriteFile(path, []byte(strings.Join(updated, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCronExpr_Describe(t *testing.T) {
+	testGolden(t, "testdata/describe.golden", (*CronExpr).Describe)
+}
+
+func TestCronExpr_DescribeLocale(t *testing.T) {
+	locales := []struct {
+		name   string
+		locale Locale
+	}{
+		{"en_us", EnglishUS},
+		{"zh", Chinese},
+		{"de", German},
+	}
+	for _, l := range locales {
+		t.Run(l.name, func(t *testing.T) {
+			testGolden(t, "testdata/describe_"+l.name+".golden", func(c *CronExpr) string {
+				return c.DescribeLocale(l.locale)
+			})
+		})
+	}
+}
+
+func TestLocale_Message(t *testing.T) {
+	for _, l := range []Locale{English, EnglishUS, Chinese, German} {
+		for id := Message(0); id < messageCount; id++ {
+			if msg := l.Message(id, 1); strings.Contains(msg, "MISSING") {
+				t.Errorf("Locale.Message(%d) = %v", id, msg)
+			}
+		}
+	}
+}
+
+func Test_analyze(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"* * * * * *", "{0 0 0 []}"},
+		{"*/15 * * * * *", "{1 0 15 []}"},
+		{"5/15 * * * * *", "{1 5 15 []}"},
+		{"0,30 * * * * *", "{1 0 30 []}"},
+		{"6,19 * * * * *", "{2 0 0 [{6 6} {19 19}]}"},
+		{"1-5,7,9-10 * * * * *", "{2 0 0 [{1 5} {7 7} {9 10}]}"},
+		{"0/7 * * * * *", "{1 0 7 []}"},
+		{"0-40/10 * * * * *", "{2 0 0 [{0 0} {10 10} {20 20} {30 30} {40 40}]}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, err := New(tt.expression, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(analyze(c.seconds, 0, 59)); got != tt.want {
+				t.Errorf("analyze() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tzPrefix of the location in the text form, "CRON_TZ=" is accepted as well when decoding
+const tzPrefix = "TZ="
+
+// MarshalText implements encoding.TextMarshaler, the text is the expression prefixed with its location,
+// e.g. "TZ=Asia/Shanghai 0 0 9 * * *". Encoders using TextMarshaler such as JSON and YAML get the same form.
+// Days of week of an expression parsed with QuartzWeekdays are written as names, which read the same in both
+// numberings, e.g. "0 0 9 ? * 2-6" is written as "0 0 9 ? * MON-FRI".
+// ErrNotRepresentable is returned if the location has no IANA name, e.g. a time.FixedZone.
+func (c CronExpr) MarshalText() ([]byte, error) {
+	expression := c.expression
+	if c.options.Weekdays == QuartzWeekdays && strings.Fields(expression)[5] != "?" {
+		expression = c.replaceField(5, formatField(analyze(c.daysOfWeek, 0, 6), 0, c.weekday(true), weekdayNames[6]))
+	}
+	if c.location == nil {
+		return []byte(expression), nil
+	}
+	name := c.location.String()
+	if _, err := time.LoadLocation(name); err != nil {
+		return nil, fmt.Errorf("%w: location %s has no IANA name", ErrNotRepresentable, name)
+	}
+	return []byte(tzPrefix + name + " " + expression), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the expression is parsed in time.Local without a location prefix
+func (c *CronExpr) UnmarshalText(text []byte) error {
+	expression, location, err := splitLocation(string(text))
+	if err != nil {
+		return err
+	}
+	parsed, err := New(expression, location)
+	if err != nil {
+		return err
+	}
+	*c = *parsed
+	return nil
+}
+
+// splitLocation split the "TZ=" or "CRON_TZ=" prefix from the expression
+func splitLocation(text string) (string, *time.Location, error) {
+	text = strings.TrimSpace(text)
+	for _, prefix := range []string{tzPrefix, "CRON_" + tzPrefix} {
+		if !strings.HasPrefix(text, prefix) {
+			continue
+		}
+		name, expression, _ := strings.Cut(text[len(prefix):], " ")
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid location '%s' in expression \"%s\": %w", name, text, err)
+		}
+		return strings.TrimSpace(expression), location, nil
+	}
+	return text, time.Local, nil
+}
@@ -0,0 +1,162 @@
+package gocronexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronExpr_MarshalText(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		expression string
+		location   *time.Location
+		want       string
+	}{
+		{"0 0 9 * * MON-FRI", shanghai, "TZ=Asia/Shanghai 0 0 9 * * MON-FRI"},
+		{"*/5 * * * * *", time.UTC, "TZ=UTC */5 * * * * *"},
+		// time.Local is named after the TZ environment variable if it's set, "Local" otherwise
+		{"0 0 * * * *", time.Local, "TZ=" + time.Local.String() + " 0 0 * * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			c, _ := New(tt.expression, tt.location)
+			text, err := c.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tt.want {
+				t.Errorf("CronExpr.MarshalText() = %v, want %v", string(text), tt.want)
+			}
+			var got CronExpr
+			if err := got.UnmarshalText(text); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.expression || got.location.String() != tt.location.String() {
+				t.Errorf("CronExpr.UnmarshalText() = %v in %v, want %v in %v", got.String(), got.location,
+					tt.expression, tt.location)
+			}
+		})
+	}
+}
+
+func TestCronExpr_MarshalText_fixedZone(t *testing.T) {
+	c, err := New("0 0 9 * * *", time.FixedZone("UTC+8", 8*60*60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := c.MarshalText(); !errors.Is(err, ErrNotRepresentable) {
+		t.Errorf("CronExpr.MarshalText() = %s, %v, want ErrNotRepresentable", text, err)
+	}
+}
+
+func TestCronExpr_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     string
+		location string
+		wantErr  bool
+	}{
+		{"0 0 9 * * *", "0 0 9 * * *", time.Local.String(), false},
+		{"CRON_TZ=UTC 0 0 9 * * *", "0 0 9 * * *", "UTC", false},
+		{"  TZ=UTC   0 0 9 * * *  ", "0 0 9 * * *", "UTC", false},
+		{"TZ=Mars/Olympus 0 0 9 * * *", "", "", true},
+		{"TZ=UTC 0 0 9 * *", "", "", true},
+		{"0 0 0 30 2 *", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got CronExpr
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CronExpr.UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.String() != tt.want || got.location.String() != tt.location) {
+				t.Errorf("CronExpr.UnmarshalText() = %v in %v, want %v in %v", got.String(), got.location, tt.want, tt.location)
+			}
+		})
+	}
+}
+
+func TestCronExpr_MarshalText_quartzWeekdays(t *testing.T) {
+	quartz := &ParseOptions{Weekdays: QuartzWeekdays}
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"0 0 9 ? * 2-6", "TZ=UTC 0 0 9 ? * MON-FRI"},
+		{"0 0 9 ? * 1,7", "TZ=UTC 0 0 9 ? * */6"},
+		{"0 0 9 ? * 2/2", "TZ=UTC 0 0 9 ? * MON-SAT/2"},
+		{"0 0 9 1 * ?", "TZ=UTC 0 0 9 1 * ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, err := NewWithOptions(tt.expression, time.UTC, quartz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			text, err := json.Marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, _ := json.Marshal(tt.want); string(text) != string(want) {
+				t.Errorf("json.Marshal() = %s, want %s", text, want)
+			}
+			var got CronExpr
+			if err := json.Unmarshal(text, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(c) {
+				t.Errorf("json.Unmarshal() = %v, want equal to %v", got.Canonical(true), c.Canonical(true))
+			}
+
+			value, err := c.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var scanned CronExpr
+			if err := scanned.Scan(value); err != nil {
+				t.Fatal(err)
+			}
+			if !scanned.Equal(c) {
+				t.Errorf("CronExpr.Scan() = %v, want equal to %v", scanned.Canonical(true), c.Canonical(true))
+			}
+		})
+	}
+}
+
+func TestCronExpr_json(t *testing.T) {
+	type config struct {
+		Schedule *CronExpr `json:"schedule"`
+		Backup   CronExpr  `json:"backup"`
+	}
+	input := `{"schedule":"TZ=UTC 0 */15 * * * *","backup":"TZ=UTC 0 0 3 * * *"}`
+	var cfg config
+	if err := json.Unmarshal([]byte(input), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC)
+	if next, _ := cfg.Schedule.Next(&base); !next.Equal(base.Add(14 * time.Minute)) {
+		t.Errorf("Next() = %v", next)
+	}
+	output, err := json.Marshal(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != input {
+		t.Errorf("json.Marshal() = %s, want %s", output, input)
+	}
+
+	// the value receiver encodes CronExpr held by value in an unaddressable struct
+	if output, err := json.Marshal(cfg); err != nil || string(output) != input {
+		t.Errorf("json.Marshal() = %s, %v, want %s", output, err, input)
+	}
+
+	if err := json.Unmarshal([]byte(`{"schedule":"0 0 0 31 4 *"}`), &cfg); err == nil {
+		t.Errorf("json.Unmarshal() error = nil, want never fires")
+	}
+}
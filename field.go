@@ -0,0 +1,122 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"github.com/bits-and-blooms/bitset"
+)
+
+type patternKind int
+
+const (
+	// every value of the field
+	patternAll patternKind = iota
+	// start/step up to the maximum
+	patternStep
+	// list of values and ranges
+	patternList
+)
+
+// span is an inclusive range of values, from == to for a single value
+type span struct {
+	from int
+	to   int
+}
+
+// fieldPattern is the most compact form of a parsed field
+type fieldPattern struct {
+	kind  patternKind
+	start int
+	step  int
+	spans []span
+}
+
+// analyze the values of bits in [min, max]
+func analyze(bits *bitset.BitSet, min int, max int) fieldPattern {
+	var values []int
+	for i := min; i <= max; i++ {
+		if bits.Test(uint(i)) {
+			values = append(values, i)
+		}
+	}
+	if len(values) == max-min+1 {
+		return fieldPattern{kind: patternAll}
+	}
+	if start, step, ok := detectStep(values, min, max); ok {
+		return fieldPattern{kind: patternStep, start: start, step: step}
+	}
+
+	p := fieldPattern{kind: patternList}
+	for _, v := range values {
+		if n := len(p.spans); n > 0 && p.spans[n-1].to == v-1 {
+			p.spans[n-1].to = v
+		} else {
+			p.spans = append(p.spans, span{v, v})
+		}
+	}
+	return p
+}
+
+// detectStep reports whether values are start, start+step, ... up to max and can't be extended downwards.
+// Two values only make a step when the first one is min, "6,19" of hours is not "6/13".
+func detectStep(values []int, min int, max int) (int, int, bool) {
+	if len(values) < 2 {
+		return 0, 0, false
+	}
+	step := values[1] - values[0]
+	if step < 2 {
+		return 0, 0, false
+	}
+	for i := 2; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0, 0, false
+		}
+	}
+	if values[0]-min >= step || values[len(values)-1]+step <= max {
+		return 0, 0, false
+	}
+	if len(values) == 2 && values[0] != min {
+		return 0, 0, false
+	}
+	return values[0], step, true
+}
+
+// single reports the value of a pattern which has only one value
+func (p fieldPattern) single() (int, bool) {
+	if p.kind == patternList && len(p.spans) == 1 && p.spans[0].from == p.spans[0].to {
+		return p.spans[0].from, true
+	}
+	return 0, false
+}
+
+// isRange reports whether the pattern is one range of multiple values
+func (p fieldPattern) isRange() bool {
+	return p.kind == patternList && len(p.spans) == 1 && p.spans[0].from != p.spans[0].to
+}
+
+// singles reports whether the pattern is a list of single values
+func (p fieldPattern) singles() ([]int, bool) {
+	if p.kind != patternList {
+		return nil, false
+	}
+	values := make([]int, 0, len(p.spans))
+	for _, s := range p.spans {
+		if s.from != s.to {
+			return nil, false
+		}
+		values = append(values, s.from)
+	}
+	return values, true
+}
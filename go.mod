@@ -1,5 +1,5 @@
 module github.com/dongfg/gocronexpr
 
-go 1.12
+go 1.21
 
 require github.com/bits-and-blooms/bitset v1.13.0
@@ -15,8 +15,10 @@
 package gocronexpr
 
 import (
+	"context"
 	"fmt"
 	"github.com/bits-and-blooms/bitset"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
@@ -26,6 +28,7 @@ import (
 type CronExpr struct {
 	expression string
 	location   *time.Location
+	options    ParseOptions
 
 	months      *bitset.BitSet
 	daysOfMonth *bitset.BitSet
@@ -35,12 +38,62 @@ type CronExpr struct {
 	seconds     *bitset.BitSet
 }
 
+// WeekdayNumbering is how numbers of the day of week field are read
+type WeekdayNumbering int
+
+const (
+	// SpringWeekdays numbers days of week 0-7, Sunday is 0 or 7
+	SpringWeekdays WeekdayNumbering = iota
+	// QuartzWeekdays numbers days of week 1-7, Sunday is 1 and Saturday is 7
+	QuartzWeekdays
+)
+
+// ParseOptions changes how expressions are read. String and Canonical number days of week as the options,
+// parse them with the same options again. MarshalText writes days of week as names which read the same in all.
+type ParseOptions struct {
+	// Weekdays is the numbering of days of week, names like "MON" are the same in all numberings
+	Weekdays WeekdayNumbering
+	// StrictQuestionMark requires '?' as the whole day of month or day of week field but not both, like Quartz.
+	// Otherwise '?' is read as '*' anywhere in the day fields.
+	StrictQuestionMark bool
+}
+
 // ScheduleOptions by cron expr
 type ScheduleOptions struct {
+	// Name identifies the job, it is used as the lock key together with the scheduled time.
+	// Jobs without a name are locked by the String of their schedule, name jobs sharing a schedule and a Locker.
+	Name string
+	// Locker is consulted before each fire, the occurrence is skipped if the lock is not acquired
+	Locker Locker
+
+	// Retry failed executions, nil means no retry
+	Retry *RetryPolicy
+	// Timeout of each attempt, the job's context is canceled after it and the attempt fails with ErrTimeout.
+	// 0 means no timeout.
+	Timeout time.Duration
+	// MisfireThreshold is how late an occurrence may start, later occurrences are reported as misfired
+	// and not executed. 0 means never misfire.
+	MisfireThreshold time.Duration
+	// SkipOverlap runs executions in background so they don't block the schedule, an occurrence is skipped
+	// with ErrOverlap while the previous execution is still running. By default executions run one at a time
+	// and an occurrence passed during an execution starts right after it.
+	// With SkipOverlap the Observer, Metrics and Logger are called from the schedule and the execution at
+	// the same time and must be safe for concurrent use. Executed, Failed and RetryPolicy.Attempted are
+	// only called from one execution at a time.
+	SkipOverlap bool
+	// Observer receives lifecycle events of the job
+	Observer Observer
+	// Metrics collects run counts, failures, durations and schedule lag of the job
+	Metrics *Metrics
+	// Logger receives scheduler diagnostics, nil discards them
+	Logger *slog.Logger
+
 	Start    *time.Time
 	End      *time.Time
 	Executed func()
-	Finish   func()
+	// Failed is called when an execution still fails after all attempts
+	Failed func(err error)
+	Finish func()
 }
 
 type calendar struct {
@@ -68,6 +121,11 @@ const (
 
 // New cron expr, return error if parse fail
 func New(expression string, location *time.Location) (*CronExpr, error) {
+	return NewWithOptions(expression, location, nil)
+}
+
+// NewWithOptions cron expr read with the options, nil options are the same as New
+func NewWithOptions(expression string, location *time.Location, options *ParseOptions) (*CronExpr, error) {
 	c := &CronExpr{
 		expression: expression,
 		location:   location,
@@ -79,6 +137,9 @@ func New(expression string, location *time.Location) (*CronExpr, error) {
 		minutes:     bitset.New(60),
 		seconds:     bitset.New(60),
 	}
+	if options != nil {
+		c.options = *options
+	}
 
 	if err := c.parse(); err != nil {
 		return c, err
@@ -109,34 +170,55 @@ func (c *CronExpr) Next(t *time.Time) (time.Time, error) {
 	return cal.time, nil
 }
 
-// Run function periodically by cron expr
-func (c *CronExpr) Run(fn func(), options *ScheduleOptions) {
+// Prev time calculated based on the given time, the latest fire before it.
+func (c *CronExpr) Prev(t *time.Time) (time.Time, error) {
 	base := time.Now()
-	for {
-		next, err := c.Next(&base)
-		if err != nil {
-			fmt.Println("error get next run time", err)
-			return
-		}
-
-		// stop after end time
-		if options.End != nil && next.After(*options.End) {
-			break
+	if t != nil {
+		base = *t
+	}
+	base = base.In(c.location)
+	today := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, time.UTC)
+	for day := today; !day.Before(today.AddDate(-maxGapYears, 0, 0)); day = day.AddDate(0, 0, -1) {
+		if !c.months.Test(uint(day.Month()-1)) || !c.daysOfMonth.Test(uint(day.Day())) || !c.daysOfWeek.Test(uint(day.Weekday())) {
+			continue
 		}
-		// start after start time
-		if options.Start != nil && next.After(*options.Start) {
-			next = *options.Start
+		// later hours of today are after base
+		hour := 23
+		if day.Equal(today) {
+			hour = base.Hour()
 		}
-		<-time.After(next.Sub(base))
-		fn()
-		if options.Executed != nil {
-			options.Executed()
+		for h, ok := prevSet(c.hours, hour); ok; h, ok = prevSet(c.hours, h-1) {
+			for m, ok := prevSet(c.minutes, 59); ok; m, ok = prevSet(c.minutes, m-1) {
+				for s, ok := prevSet(c.seconds, 59); ok; s, ok = prevSet(c.seconds, s-1) {
+					prev := time.Date(day.Year(), day.Month(), day.Day(), h, m, s, 0, c.location)
+					if prev.Before(base) {
+						return prev, nil
+					}
+				}
+			}
 		}
-		base = next
-	}
-	if options.Finish != nil {
-		options.Finish()
 	}
+	return time.Time{}, fmt.Errorf("invalid cron expression \"%s\" led to runaway search for previous trigger", c.expression)
+}
+
+// Run function periodically by cron expr
+func (c *CronExpr) Run(fn func(), options *ScheduleOptions) {
+	c.RunE(func() error {
+		fn()
+		return nil
+	}, options)
+}
+
+// RunE run error-returning function periodically by cron expr, failed executions are retried by options.Retry
+func (c *CronExpr) RunE(fn func() error, options *ScheduleOptions) {
+	c.RunContext(context.Background(), func(context.Context) error {
+		return fn()
+	}, options)
+}
+
+// RunContext run function periodically by cron expr until ctx is done, see RunSchedule
+func (c *CronExpr) RunContext(ctx context.Context, fn func(ctx context.Context) error, options *ScheduleOptions) {
+	RunSchedule(ctx, c, fn, options)
 }
 
 func (c *CronExpr) doNext(cal *calendar, dot int) error {
@@ -186,7 +268,7 @@ func (c *CronExpr) doNext(cal *calendar, dot int) error {
 	month := cal.month
 	updateMonth := c.findNext(c.months, month, cal, constMonth, constYear, resets)
 	if month != updateMonth {
-		if cal.year-dot > 4 {
+		if cal.year-dot > maxGapYears {
 			return fmt.Errorf("invalid cron expression \"%s\" led to runaway search for next trigger", c.expression)
 		}
 		if err := c.doNext(cal, dot); err != nil {
@@ -235,6 +317,11 @@ func (c *CronExpr) parse() error {
 	if len(fields) != 6 {
 		return fmt.Errorf("cron expression must consist of 6 fields (found %d in \"%s\")", len(fields), c.expression)
 	}
+	if c.options.StrictQuestionMark {
+		if err := c.checkQuestionMarks(fields); err != nil {
+			return err
+		}
+	}
 
 	if err := c.setNumberHits(c.seconds, fields[0], 0, 60); err != nil {
 		return err
@@ -251,16 +338,30 @@ func (c *CronExpr) parse() error {
 	if err := c.setMonths(c.months, fields[4]); err != nil {
 		return err
 	}
-	if err := c.setDays(c.daysOfWeek, replaceOrdinals(fields[5], "SUN,MON,TUE,WED,THU,FRI,SAT"), 8); err != nil {
+	if err := c.setDaysOfWeek(c.daysOfWeek, fields[5]); err != nil {
 		return err
 	}
 
-	if c.daysOfWeek.Test(7) {
-		// Sunday can be represented as 0 or 7
-		c.daysOfWeek.Set(0)
-		c.daysOfWeek.Clear(7)
-	}
+	return c.validate()
+}
 
+// checkQuestionMarks requires '?' as exactly one of the day fields and nowhere else
+func (c *CronExpr) checkQuestionMarks(fields []string) error {
+	for i, field := range fields {
+		if !strings.Contains(field, "?") || field == "?" && (i == 3 || i == 5) {
+			continue
+		}
+		if i == 3 || i == 5 {
+			return fmt.Errorf("'?' must be the whole %s field: '%s' in expression \"%s\"", fieldNames[i], field, c.expression)
+		}
+		return fmt.Errorf("'?' is only allowed in day of month or day of week: '%s' in expression \"%s\"", field, c.expression)
+	}
+	switch {
+	case fields[3] == "?" && fields[5] == "?":
+		return fmt.Errorf("'?' can't be in both day of month and day of week in expression \"%s\"", c.expression)
+	case fields[3] != "?" && fields[5] != "?":
+		return fmt.Errorf("one of day of month and day of week must be '?' in expression \"%s\"", c.expression)
+	}
 	return nil
 }
 
@@ -275,18 +376,51 @@ func replaceOrdinals(value string, commaSeparatedList string) string {
 
 func (c *CronExpr) setDaysOfMonth(bits *bitset.BitSet, field string) error {
 	max := 31
-	if err := c.setDays(bits, field, max+1); err != nil {
+	if err := c.setDays(bits, field, 0, max+1); err != nil {
 		return err
 	}
 	bits.Clear(0)
 	return nil
 }
 
-func (c *CronExpr) setDays(bits *bitset.BitSet, field string, max int) error {
+func (c *CronExpr) setDaysOfWeek(bits *bitset.BitSet, field string) error {
+	if c.options.Weekdays == QuartzWeekdays {
+		return c.setQuartzDaysOfWeek(bits, field)
+	}
+	if err := c.setDays(bits, replaceOrdinals(field, "SUN,MON,TUE,WED,THU,FRI,SAT"), 0, 8); err != nil {
+		return err
+	}
+	if bits.Test(7) {
+		// Sunday can be represented as 0 or 7
+		bits.Set(0)
+		bits.Clear(7)
+	}
+	return nil
+}
+
+func (c *CronExpr) setQuartzDaysOfWeek(bits *bitset.BitSet, field string) error {
+	for _, item := range strings.Split(field, ",") {
+		for _, v := range strings.Split(strings.Split(item, "/")[0], "-") {
+			if v == "0" {
+				return fmt.Errorf("day of week 0 is invalid in Quartz numbering, Sunday is 1: '%s' in expression \"%s\"", item, c.expression)
+			}
+		}
+	}
+	days := bitset.New(8)
+	if err := c.setDays(days, replaceOrdinals(field, "FOO,SUN,MON,TUE,WED,THU,FRI,SAT"), 1, 8); err != nil {
+		return err
+	}
+	for i, ok := days.NextSet(1); ok; i, ok = days.NextSet(i + 1) {
+		bits.Set(i - 1)
+	}
+	return nil
+}
+
+func (c *CronExpr) setDays(bits *bitset.BitSet, field string, min int, max int) error {
 	if strings.Contains(field, "?") {
 		field = "*"
 	}
-	return c.setNumberHits(bits, field, 0, max)
+	return c.setNumberHits(bits, field, min, max)
 }
 
 func (c *CronExpr) setMonths(bits *bitset.BitSet, value string) error {
@@ -389,7 +523,19 @@ func setRange(b *bitset.BitSet, from int, to int) {
 	}
 }
 
+// prevSet return the greatest set bit not after i
+func prevSet(b *bitset.BitSet, i int) (int, bool) {
+	for ; i >= 0; i-- {
+		if b.Test(uint(i)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 func newCalendar(t time.Time, location *time.Location) *calendar {
+	// the wall clock of t is only meaningful in the location of the expression
+	t = t.In(location)
 	cal := &calendar{
 		loc:   location,
 		year:  t.Year(),
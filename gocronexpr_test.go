@@ -182,7 +182,7 @@ func Test_cronexpr_Next(t *testing.T) {
 				t.Errorf("CronExpr.New() error = %v, expression %v", err, tt.expression)
 				return
 			}
-			base, _ := time.Parse("2006-01-02 15:04:05", tt.baseTime)
+			base, _ := time.ParseInLocation("2006-01-02 15:04:05", tt.baseTime, time.Local)
 			got, err := c.Next(&base)
 			if err != nil {
 				t.Errorf("CronExpr.Next() error = %v, expression %v", err, tt.expression)
@@ -194,3 +194,153 @@ func Test_cronexpr_Next(t *testing.T) {
 		})
 	}
 }
+
+func Test_cronexpr_Prev(t *testing.T) {
+	tests := []struct {
+		expression string
+		baseTime   string
+		want       string
+	}{
+		{"*/15 * 1-4 * * *", "2012-07-02 01:00:00", "2012-07-01 04:59:45"},
+		{"0 */2 * * * *", "2012-07-01 09:00:00", "2012-07-01 08:58:00"},
+		{"0 */2 * * * *", "2012-07-01 09:00:01", "2012-07-01 09:00:00"},
+		{"* * * * * *", "2012-07-01 00:00:00", "2012-06-30 23:59:59"},
+		{"10 * * * * *", "2012-12-01 09:42:10", "2012-12-01 09:41:10"},
+		{"0 0 0 1 * *", "2011-01-01 00:00:00", "2010-12-01 00:00:00"},
+		{"0 0 0 31 * *", "2011-12-01 00:00:00", "2011-10-31 00:00:00"},
+		{"* * * * * 2", "2010-10-27 15:12:42", "2010-10-26 23:59:59"},
+		{"0 0 0 29 2 *", "2012-02-29 00:00:00", "2008-02-29 00:00:00"},
+		{"0 0 7 ? * MON-FRI", "2009-09-28 07:00:00", "2009-09-25 07:00:00"},
+		{"0 30 23 30 1/3 ?", "2011-01-30 23:30:00", "2010-10-30 23:30:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression+" "+tt.baseTime, func(t *testing.T) {
+			c, err := New(tt.expression, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			base, _ := time.ParseInLocation("2006-01-02 15:04:05", tt.baseTime, time.Local)
+			got, err := c.Prev(&base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Format("2006-01-02 15:04:05") != tt.want {
+				t.Errorf("CronExpr.Prev() = %v, want %v", got.Format("2006-01-02 15:04:05"), tt.want)
+			}
+			// nothing fires between the previous time and base
+			if next, _ := c.Next(&got); next.Before(base) {
+				t.Errorf("CronExpr.Next(%v) = %v, before %v", got, next, base)
+			}
+		})
+	}
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	c, _ := New("0 30 2 * * *", shanghai)
+	// 02:00 in Shanghai, before 02:30 of the day
+	base := time.Date(2019, 12, 31, 18, 0, 0, 0, time.UTC)
+	if got, _ := c.Prev(&base); !got.Equal(time.Date(2019, 12, 31, 2, 30, 0, 0, shanghai)) {
+		t.Errorf("CronExpr.Prev() = %v, want 2019-12-31 02:30 in Shanghai", got)
+	}
+}
+
+func Test_cronexpr_Next_location(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	c, _ := New("0 30 2 * * *", shanghai)
+	tests := []struct {
+		base time.Time
+		want time.Time
+	}{
+		// 08:01 in Shanghai, after 02:30 of the day
+		{time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC), time.Date(2020, 1, 2, 2, 30, 0, 0, shanghai)},
+		// 02:00 in Shanghai, before 02:30 of the day
+		{time.Date(2019, 12, 31, 18, 0, 0, 0, time.UTC), time.Date(2020, 1, 1, 2, 30, 0, 0, shanghai)},
+		{time.Date(2020, 1, 1, 8, 1, 0, 0, shanghai), time.Date(2020, 1, 2, 2, 30, 0, 0, shanghai)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.base.String(), func(t *testing.T) {
+			got, err := c.Next(&tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) || !got.After(tt.base) {
+				t.Errorf("CronExpr.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWithOptions_weekdays(t *testing.T) {
+	quartz := &ParseOptions{Weekdays: QuartzWeekdays}
+	tests := []struct {
+		expression string
+		options    *ParseOptions
+		want       string
+		wantErr    bool
+	}{
+		{"0 0 9 * * 2-6", quartz, "0 0 9 * * 2-6", false},
+		{"0 0 9 ? * 1", quartz, "0 0 9 * * 1", false},
+		{"0 0 9 ? * 7", quartz, "0 0 9 * * 7", false},
+		{"0 0 9 ? * */2", quartz, "0 0 9 * * */2", false},
+		{"0 0 9 ? * 2/2", quartz, "0 0 9 * * 2-7/2", false},
+		{"0 0 9 ? * MON-FRI", quartz, "0 0 9 * * 2-6", false},
+		{"0 0 9 ? * SUN,SAT", quartz, "0 0 9 * * */6", false},
+		{"0 0 9 ? * *", quartz, "0 0 9 * * *", false},
+		{"0 0 9 ? * 0", quartz, "", true},
+		{"0 0 9 ? * 0-3", quartz, "", true},
+		{"0 0 9 ? * 1,0/2", quartz, "", true},
+		{"0 0 9 ? * 8", quartz, "", true},
+		{"0 0 9 * * 1-5", nil, "0 0 9 * * 1-5", false},
+		{"0 0 9 * * 7", &ParseOptions{}, "0 0 9 * * 0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, err := NewWithOptions(tt.expression, time.UTC, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if c.Canonical(false) != tt.want {
+				t.Errorf("Canonical() = %v, want %v", c.Canonical(false), tt.want)
+			}
+			if reparsed, err := NewWithOptions(c.Canonical(false), time.UTC, tt.options); err != nil || !reparsed.Equal(c) {
+				t.Errorf("Canonical() = %v reads differently with the same options, error = %v", c.Canonical(false), err)
+			}
+		})
+	}
+}
+
+func TestNewWithOptions_strictQuestionMark(t *testing.T) {
+	strict := &ParseOptions{StrictQuestionMark: true}
+	tests := []struct {
+		expression string
+		options    *ParseOptions
+		wantErr    string
+	}{
+		{"0 0 9 ? * MON-FRI", strict, ""},
+		{"0 0 9 1 * ?", strict, ""},
+		{"0 0 9 ? * 2-6", &ParseOptions{Weekdays: QuartzWeekdays, StrictQuestionMark: true}, ""},
+		{"0 0 9 * * MON-FRI", strict, "one of day of month and day of week must be '?' in expression \"0 0 9 * * MON-FRI\""},
+		{"0 0 9 ? * ?", strict, "'?' can't be in both day of month and day of week in expression \"0 0 9 ? * ?\""},
+		{"0 0 9 1? * *", strict, "'?' must be the whole day of month field: '1?' in expression \"0 0 9 1? * *\""},
+		{"0 0 9 1 * ?,MON", strict, "'?' must be the whole day of week field: '?,MON' in expression \"0 0 9 1 * ?,MON\""},
+		{"0 ? 9 1 * ?", strict, "'?' is only allowed in day of month or day of week: '?' in expression \"0 ? 9 1 * ?\""},
+		{"0 0 9 1? * *", nil, ""},
+		{"0 0 9 ? * ?", &ParseOptions{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := NewWithOptions(tt.expression, time.UTC, tt.options)
+			if err == nil && tt.wantErr != "" || err != nil && err.Error() != tt.wantErr {
+				t.Errorf("NewWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
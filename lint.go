@@ -0,0 +1,170 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LintCode identifies the kind of a lint warning
+type LintCode string
+
+const (
+	// LintEverySecond seconds are "*" while a higher field is restricted, e.g. "* 0 * * * *" fires 60 times an hour
+	LintEverySecond LintCode = "every-second"
+	// LintEveryMinute minutes are "*" within a single hour, e.g. "0 * 9 * * *" fires 60 times a day
+	LintEveryMinute LintCode = "every-minute"
+	// LintUnevenStep step doesn't divide the range, e.g. "*/7" of minutes fires at 56 then 0 of the next hour
+	LintUnevenStep LintCode = "uneven-step"
+	// LintSkippedMonths days of month don't occur in some months, e.g. "0 0 0 31 * *" skips April
+	LintSkippedMonths LintCode = "skipped-months"
+	// LintDayOfMonthAndWeek both days of month and days of week are restricted, they must match at the same time
+	LintDayOfMonthAndWeek LintCode = "day-of-month-and-week"
+)
+
+// Warning of a suspicious but valid expression
+type Warning struct {
+	Code LintCode
+	// Field is the name of the field, e.g. "minute"
+	Field   string
+	Message string
+	// Fix is the suggested expression, empty if there is no obvious fix
+	Fix string
+}
+
+func (w Warning) String() string {
+	if w.Fix == "" {
+		return fmt.Sprintf("%s: %s", w.Code, w.Message)
+	}
+	return fmt.Sprintf("%s: %s, did you mean \"%s\"?", w.Code, w.Message, w.Fix)
+}
+
+// Lint the expression for common mistakes, return nil if nothing is suspicious
+func (c *CronExpr) Lint() []Warning {
+	var patterns [6]fieldPattern
+	for i, bits := range c.sets() {
+		patterns[i] = analyze(bits, fieldBounds[i][0], fieldBounds[i][1])
+	}
+	seconds, minutes, hours := patterns[0], patterns[1], patterns[2]
+	daysOfMonth, daysOfWeek := patterns[3], patterns[5]
+
+	var warnings []Warning
+	if seconds.kind == patternAll && (minutes.kind != patternAll || hours.kind != patternAll) {
+		warnings = append(warnings, Warning{
+			Code:    LintEverySecond,
+			Field:   fieldNames[0],
+			Message: "fires every second of the matching minutes",
+			Fix:     c.replaceField(0, "0"),
+		})
+	}
+	if _, ok := hours.single(); ok && minutes.kind == patternAll && seconds.kind != patternAll {
+		warnings = append(warnings, Warning{
+			Code:    LintEveryMinute,
+			Field:   fieldNames[1],
+			Message: "fires every minute of the matching hour",
+			Fix:     c.replaceField(1, "0"),
+		})
+	}
+	// days of month vary by month, their steps are uneven anyway
+	for _, i := range []int{0, 1, 2, 4, 5} {
+		p, size := patterns[i], fieldBounds[i][1]-fieldBounds[i][0]+1
+		if p.kind != patternStep || size%p.step == 0 {
+			continue
+		}
+		name := strconv.Itoa
+		switch i {
+		case 4:
+			name = func(v int) string { return strconv.Itoa(v + 1) }
+		case 5:
+			name = c.weekday(false)
+		}
+		last := p.start + (fieldBounds[i][1]-p.start)/p.step*p.step
+		warning := Warning{
+			Code:  LintUnevenStep,
+			Field: fieldNames[i],
+			Message: fmt.Sprintf("step %d doesn't divide %d, the interval from %s to %s is %d",
+				p.step, size, name(last), name(p.start), p.start+size-last),
+		}
+		// 7 days of week have no divisor
+		if p.step = nearestDivisor(size, p.step); p.step < size {
+			warning.Fix = c.replaceField(i, formatField(p, fieldBounds[i][0], name, ""))
+		}
+		warnings = append(warnings, warning)
+	}
+	if daysOfMonth.kind != patternAll {
+		if first, skipped := c.skippedMonths(); len(skipped) > 0 {
+			warnings = append(warnings, Warning{
+				Code:    LintSkippedMonths,
+				Field:   fieldNames[3],
+				Message: fmt.Sprintf("skips the months without day %d: %s", first, strings.Join(skipped, ",")),
+			})
+		}
+	}
+	if daysOfMonth.kind != patternAll && daysOfWeek.kind != patternAll {
+		warnings = append(warnings, Warning{
+			Code:    LintDayOfMonthAndWeek,
+			Field:   fieldNames[5],
+			Message: "fires only when the day of month and the day of week both match",
+		})
+	}
+	return warnings
+}
+
+// replaceField return the expression with the i-th field replaced
+func (c *CronExpr) replaceField(i int, value string) string {
+	fields := strings.Fields(c.expression)
+	fields[i] = value
+	return strings.Join(fields, " ")
+}
+
+// skippedMonths of the expression shorter than its first day, February counts as 28 days.
+// The only month of the expression is not skipped, e.g. "0 0 0 29 2 *" means leap days.
+func (c *CronExpr) skippedMonths() (int, []string) {
+	first, _ := c.daysOfMonth.NextSet(1)
+	var skipped []string
+	if c.months.Count() == 1 {
+		return int(first), nil
+	}
+	for m, ok := c.months.NextSet(0); ok && m < 12; m, ok = c.months.NextSet(m + 1) {
+		days := daysIn[m]
+		if m == 1 {
+			days = 28
+		}
+		if int(first) > days {
+			skipped = append(skipped, monthNames[m])
+		}
+	}
+	return int(first), skipped
+}
+
+// nearestDivisor of n to step which is at least 2, the smaller one if there are two
+func nearestDivisor(n, step int) int {
+	best := n
+	for d := 2; d < n; d++ {
+		if n%d == 0 && absInt(d-step) < absInt(best-step) {
+			best = d
+		}
+	}
+	return best
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package gocronexpr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCronExpr_Lint(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []Warning
+	}{
+		{"0 0 * * * *", nil},
+		{"* * * * * *", nil},
+		{"0 */15 9-17 * * MON-FRI", nil},
+		{"* 0 * * * *", []Warning{
+			{LintEverySecond, "second", "fires every second of the matching minutes", "0 0 * * * *"},
+		}},
+		{"0 * 9 * * *", []Warning{
+			{LintEveryMinute, "minute", "fires every minute of the matching hour", "0 0 9 * * *"},
+		}},
+		{"0 */7 * * * *", []Warning{
+			{LintUnevenStep, "minute", "step 7 doesn't divide 60, the interval from 56 to 0 is 4", "0 */6 * * * *"},
+		}},
+		{"0 0 3/5 * * *", []Warning{
+			{LintUnevenStep, "hour", "step 5 doesn't divide 24, the interval from 23 to 3 is 4", "0 0 3/4 * * *"},
+		}},
+		{"0 0 0 1 */5 *", []Warning{
+			{LintUnevenStep, "month", "step 5 doesn't divide 12, the interval from 11 to 1 is 2", "0 0 0 1 */4 *"},
+		}},
+		{"0 0 0 * * */4", []Warning{
+			{LintUnevenStep, "day of week", "step 4 doesn't divide 7, the interval from 4 to 0 is 3", ""},
+		}},
+		{"0 0 0 31 * *", []Warning{
+			{LintSkippedMonths, "day of month", "skips the months without day 31: FEB,APR,JUN,SEP,NOV", ""},
+		}},
+		{"0 0 0 29 2 *", nil},
+		{"0 0 0 29 1,2 *", []Warning{
+			{LintSkippedMonths, "day of month", "skips the months without day 29: FEB", ""},
+		}},
+		{"0 0 0 13 * FRI", []Warning{
+			{LintDayOfMonthAndWeek, "day of week", "fires only when the day of month and the day of week both match", ""},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, err := New(tt.expression, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Lint(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CronExpr.Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarning_String(t *testing.T) {
+	w := Warning{LintEverySecond, "second", "fires every second of the matching minutes", "0 0 * * * *"}
+	want := `every-second: fires every second of the matching minutes, did you mean "0 0 * * * *"?`
+	if got := w.String(); got != want {
+		t.Errorf("Warning.String() = %v, want %v", got, want)
+	}
+}
+
+func TestCronExpr_Lint_quartzWeekdays(t *testing.T) {
+	c, err := NewWithOptions("0 0 0 ? * */4", time.UTC, &ParseOptions{Weekdays: QuartzWeekdays})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Warning{{LintUnevenStep, "day of week", "step 4 doesn't divide 7, the interval from 5 to 1 is 3", ""}}
+	if got := c.Lint(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CronExpr.Lint() = %v, want %v", got, want)
+	}
+}
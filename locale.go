@@ -0,0 +1,333 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Message identifies a phrase of descriptions
+type Message int
+
+// Messages of descriptions, the comment of each one is its English template.
+// Arguments are referenced as %[1]v and %[2]v so translations may reorder them.
+const (
+	// "at %[1]v", the argument is a list of times
+	MsgAtTimes Message = iota
+	// "every minute between %[1]v and %[2]v"
+	MsgEveryMinuteBetween
+	// "every second"
+	MsgEverySecond
+	// "every %[1]v seconds"
+	MsgEveryNSeconds
+	// "starting at %[1]v seconds past the minute"
+	MsgStartingAtSecond
+	// "seconds %[1]v through %[2]v past the minute"
+	MsgSecondsBetween
+	// "at %[1]v seconds past the minute", a single second
+	MsgAtSecond
+	// "at %[1]v seconds past the minute", a list of seconds
+	MsgAtSeconds
+	// "every minute"
+	MsgEveryMinute
+	// "every %[1]v minutes"
+	MsgEveryNMinutes
+	// "starting at %[1]v minutes past the hour"
+	MsgStartingAtMinute
+	// "minutes %[1]v through %[2]v past the hour"
+	MsgMinutesBetween
+	// "at %[1]v minutes past the hour", a single minute
+	MsgAtMinute
+	// "at %[1]v minutes past the hour", a list of minutes
+	MsgAtMinutes
+	// "every hour"
+	MsgEveryHour
+	// "every %[1]v hours"
+	MsgEveryNHours
+	// "starting at %[1]v", the argument is a time
+	MsgStartingAtTime
+	// "every hour, between %[1]v and %[2]v"
+	MsgEveryHourBetween
+	// "between %[1]v and %[2]v"
+	MsgBetweenTimes
+	// "during hours %[1]v"
+	MsgDuringHours
+	// "every hour, during hours %[1]v"
+	MsgEveryHourDuringHours
+	// "every %[1]v days of the month"
+	MsgEveryNDays
+	// "starting on day %[1]v"
+	MsgStartingOnDay
+	// "between day %[1]v and %[2]v of the month"
+	MsgDaysBetween
+	// "on day %[1]v of the month", a single day
+	MsgOnDay
+	// "on days %[1]v of the month", a list of days
+	MsgOnDays
+	// "%[1]v through %[2]v", a range of weekdays
+	MsgWeekdaysBetween
+	// "only on %[1]v"
+	MsgOnWeekdays
+	// "every %[1]v months"
+	MsgEveryNMonths
+	// "starting in %[1]v"
+	MsgStartingInMonth
+	// "%[1]v through %[2]v", a range of months
+	MsgMonthsBetween
+	// "only in %[1]v"
+	MsgInMonths
+	// "%[1]v through %[2]v", a range inside a list
+	MsgSpan
+
+	messageCount
+)
+
+// Locale renders descriptions of expressions
+type Locale interface {
+	// Message template of id for count n, formatted by fmt.Sprintf
+	Message(id Message, n int) string
+	Weekday(day time.Weekday) string
+	Month(month time.Month) string
+	// Time of day, second is 0 unless the time has seconds
+	Time(hour, minute, second int) string
+	// List join items, e.g. "a, b and c"
+	List(items []string) string
+	// Sentence join phrases of a description
+	Sentence(parts []string) string
+}
+
+// Locales shipped with the package
+var (
+	// English with 24-hour clock
+	English Locale = &locale{
+		messages:   englishMessages,
+		weekdays:   [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:     englishMonths,
+		listSep:    ", ",
+		listLast:   " and ",
+		sentence:   ", ",
+		capitalize: true,
+	}
+	// EnglishUS is English with 12-hour clock
+	EnglishUS Locale = &locale{
+		messages:   englishMessages,
+		weekdays:   [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:     englishMonths,
+		clock12:    true,
+		listSep:    ", ",
+		listLast:   " and ",
+		sentence:   ", ",
+		capitalize: true,
+	}
+	// Chinese is Simplified Chinese with 24-hour clock
+	Chinese Locale = &locale{
+		messages: map[Message][2]string{
+			MsgAtTimes:              {"在 %[1]v"},
+			MsgEveryMinuteBetween:   {"在 %[1]v 至 %[2]v 之间的每分钟"},
+			MsgEverySecond:          {"每秒"},
+			MsgEveryNSeconds:        {"每 %[1]v 秒"},
+			MsgStartingAtSecond:     {"从第 %[1]v 秒开始"},
+			MsgSecondsBetween:       {"每分钟的第 %[1]v 至 %[2]v 秒"},
+			MsgAtSecond:             {"在每分钟的第 %[1]v 秒"},
+			MsgAtSeconds:            {"在每分钟的第 %[1]v 秒"},
+			MsgEveryMinute:          {"每分钟"},
+			MsgEveryNMinutes:        {"每 %[1]v 分钟"},
+			MsgStartingAtMinute:     {"从第 %[1]v 分钟开始"},
+			MsgMinutesBetween:       {"每小时的第 %[1]v 至 %[2]v 分钟"},
+			MsgAtMinute:             {"在每小时的第 %[1]v 分钟"},
+			MsgAtMinutes:            {"在每小时的第 %[1]v 分钟"},
+			MsgEveryHour:            {"每小时"},
+			MsgEveryNHours:          {"每 %[1]v 小时"},
+			MsgStartingAtTime:       {"从 %[1]v 开始"},
+			MsgEveryHourBetween:     {"在 %[1]v 至 %[2]v 之间的每小时"},
+			MsgBetweenTimes:         {"在 %[1]v 至 %[2]v 之间"},
+			MsgDuringHours:          {"仅在 %[1]v 时"},
+			MsgEveryHourDuringHours: {"每小时，仅在 %[1]v 时"},
+			MsgEveryNDays:           {"每月每 %[1]v 天"},
+			MsgStartingOnDay:        {"从 %[1]v 号开始"},
+			MsgDaysBetween:          {"每月 %[1]v 号至 %[2]v 号"},
+			MsgOnDay:                {"每月 %[1]v 号"},
+			MsgOnDays:               {"每月 %[1]v 号"},
+			MsgWeekdaysBetween:      {"%[1]v至%[2]v"},
+			MsgOnWeekdays:           {"仅在%[1]v"},
+			MsgEveryNMonths:         {"每 %[1]v 个月"},
+			MsgStartingInMonth:      {"从%[1]v开始"},
+			MsgMonthsBetween:        {"%[1]v至%[2]v"},
+			MsgInMonths:             {"仅在%[1]v"},
+			MsgSpan:                 {"%[1]v至%[2]v"},
+		},
+		weekdays: [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		months:   [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		listSep:  "、",
+		listLast: "和",
+		sentence: "，",
+	}
+	// German with 24-hour clock
+	German Locale = &locale{
+		messages: map[Message][2]string{
+			MsgAtTimes:              {"um %[1]v"},
+			MsgEveryMinuteBetween:   {"jede Minute zwischen %[1]v und %[2]v"},
+			MsgEverySecond:          {"jede Sekunde"},
+			MsgEveryNSeconds:        {"alle %[1]v Sekunden"},
+			MsgStartingAtSecond:     {"beginnend bei Sekunde %[1]v"},
+			MsgSecondsBetween:       {"Sekunden %[1]v bis %[2]v jeder Minute"},
+			MsgAtSecond:             {"bei Sekunde %[1]v jeder Minute"},
+			MsgAtSeconds:            {"bei den Sekunden %[1]v jeder Minute"},
+			MsgEveryMinute:          {"jede Minute"},
+			MsgEveryNMinutes:        {"alle %[1]v Minuten"},
+			MsgStartingAtMinute:     {"beginnend bei Minute %[1]v"},
+			MsgMinutesBetween:       {"Minuten %[1]v bis %[2]v jeder Stunde"},
+			MsgAtMinute:             {"bei Minute %[1]v jeder Stunde"},
+			MsgAtMinutes:            {"bei den Minuten %[1]v jeder Stunde"},
+			MsgEveryHour:            {"jede Stunde"},
+			MsgEveryNHours:          {"alle %[1]v Stunden"},
+			MsgStartingAtTime:       {"beginnend um %[1]v"},
+			MsgEveryHourBetween:     {"jede Stunde zwischen %[1]v und %[2]v"},
+			MsgBetweenTimes:         {"zwischen %[1]v und %[2]v"},
+			MsgDuringHours:          {"während der Stunden %[1]v"},
+			MsgEveryHourDuringHours: {"jede Stunde während der Stunden %[1]v"},
+			MsgEveryNDays:           {"alle %[1]v Tage im Monat"},
+			MsgStartingOnDay:        {"beginnend am %[1]v. Tag"},
+			MsgDaysBetween:          {"zwischen Tag %[1]v und %[2]v des Monats"},
+			MsgOnDay:                {"am %[1]v. Tag des Monats"},
+			MsgOnDays:               {"an den Tagen %[1]v des Monats"},
+			MsgWeekdaysBetween:      {"%[1]v bis %[2]v"},
+			MsgOnWeekdays:           {"nur am %[1]v"},
+			MsgEveryNMonths:         {"alle %[1]v Monate"},
+			MsgStartingInMonth:      {"beginnend im %[1]v"},
+			MsgMonthsBetween:        {"%[1]v bis %[2]v"},
+			MsgInMonths:             {"nur im %[1]v"},
+			MsgSpan:                 {"%[1]v bis %[2]v"},
+		},
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September",
+			"Oktober", "November", "Dezember"},
+		listSep:    ", ",
+		listLast:   " und ",
+		sentence:   ", ",
+		capitalize: true,
+	}
+)
+
+// englishMessages are {singular, plural} templates
+var englishMessages = map[Message][2]string{
+	MsgAtTimes:              {"at %[1]v"},
+	MsgEveryMinuteBetween:   {"every minute between %[1]v and %[2]v"},
+	MsgEverySecond:          {"every second"},
+	MsgEveryNSeconds:        {"every %[1]v seconds"},
+	MsgStartingAtSecond:     {"starting at %[1]v second past the minute", "starting at %[1]v seconds past the minute"},
+	MsgSecondsBetween:       {"seconds %[1]v through %[2]v past the minute"},
+	MsgAtSecond:             {"at %[1]v second past the minute", "at %[1]v seconds past the minute"},
+	MsgAtSeconds:            {"at %[1]v seconds past the minute"},
+	MsgEveryMinute:          {"every minute"},
+	MsgEveryNMinutes:        {"every %[1]v minutes"},
+	MsgStartingAtMinute:     {"starting at %[1]v minute past the hour", "starting at %[1]v minutes past the hour"},
+	MsgMinutesBetween:       {"minutes %[1]v through %[2]v past the hour"},
+	MsgAtMinute:             {"at %[1]v minute past the hour", "at %[1]v minutes past the hour"},
+	MsgAtMinutes:            {"at %[1]v minutes past the hour"},
+	MsgEveryHour:            {"every hour"},
+	MsgEveryNHours:          {"every %[1]v hours"},
+	MsgStartingAtTime:       {"starting at %[1]v"},
+	MsgEveryHourBetween:     {"every hour, between %[1]v and %[2]v"},
+	MsgBetweenTimes:         {"between %[1]v and %[2]v"},
+	MsgDuringHours:          {"during hours %[1]v"},
+	MsgEveryHourDuringHours: {"every hour, during hours %[1]v"},
+	MsgEveryNDays:           {"every %[1]v days of the month"},
+	MsgStartingOnDay:        {"starting on day %[1]v"},
+	MsgDaysBetween:          {"between day %[1]v and %[2]v of the month"},
+	MsgOnDay:                {"on day %[1]v of the month"},
+	MsgOnDays:               {"on days %[1]v of the month"},
+	MsgWeekdaysBetween:      {"%[1]v through %[2]v"},
+	MsgOnWeekdays:           {"only on %[1]v"},
+	MsgEveryNMonths:         {"every %[1]v months"},
+	MsgStartingInMonth:      {"starting in %[1]v"},
+	MsgMonthsBetween:        {"%[1]v through %[2]v"},
+	MsgInMonths:             {"only in %[1]v"},
+	MsgSpan:                 {"%[1]v through %[2]v"},
+}
+
+var englishMonths = [12]string{"January", "February", "March", "April", "May", "June", "July", "August",
+	"September", "October", "November", "December"}
+
+// locale is a table based Locale
+type locale struct {
+	// messages are {singular, plural} templates, the singular one is used for both if plural is empty
+	messages map[Message][2]string
+	weekdays [7]string
+	months   [12]string
+	clock12  bool
+	listSep  string
+	listLast string
+	sentence string
+	// capitalize the first letter of sentences
+	capitalize bool
+}
+
+func (l *locale) Message(id Message, n int) string {
+	templates, ok := l.messages[id]
+	if !ok {
+		return fmt.Sprintf("%%!(MISSING MESSAGE %d)", id)
+	}
+	if n != 1 && templates[1] != "" {
+		return templates[1]
+	}
+	return templates[0]
+}
+
+func (l *locale) Weekday(day time.Weekday) string {
+	return l.weekdays[day]
+}
+
+func (l *locale) Month(month time.Month) string {
+	return l.months[month-1]
+}
+
+func (l *locale) Time(hour, minute, second int) string {
+	suffix := ""
+	if l.clock12 {
+		suffix = " AM"
+		if hour >= 12 {
+			suffix = " PM"
+		}
+		hour %= 12
+		if hour == 0 {
+			hour = 12
+		}
+	}
+	if second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d%s", hour, minute, second, suffix)
+	}
+	return fmt.Sprintf("%02d:%02d%s", hour, minute, suffix)
+}
+
+func (l *locale) List(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], l.listSep) + l.listLast + items[len(items)-1]
+}
+
+func (l *locale) Sentence(parts []string) string {
+	s := strings.Join(parts, l.sentence)
+	if !l.capitalize || s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lockRetention is how long acquired occurrences are remembered
+const lockRetention = 24 * time.Hour
+
+// Locker decides which one of several schedulers running the same job executes an occurrence.
+// TryLock reports whether the caller acquired the occurrence of job name at the scheduled time,
+// only the caller who acquired it should execute the job.
+type Locker interface {
+	TryLock(name string, scheduled time.Time) (bool, error)
+}
+
+// MemoryLocker is a Locker shared by schedulers in the same process
+type MemoryLocker struct {
+	mu   sync.Mutex
+	held map[string]time.Time
+}
+
+// NewMemoryLocker create an in-process locker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{held: make(map[string]time.Time)}
+}
+
+// TryLock acquire the occurrence, return false if it's already acquired
+func (l *MemoryLocker) TryLock(name string, scheduled time.Time) (bool, error) {
+	key := lockKey(name, scheduled)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.held[key]; ok {
+		return false, nil
+	}
+	// forget old occurrences so the map does not grow forever
+	for k, t := range l.held {
+		if t.Before(scheduled.Add(-lockRetention)) {
+			delete(l.held, k)
+		}
+	}
+	l.held[key] = scheduled
+	return true, nil
+}
+
+// FileLocker is a Locker shared by processes on the same host.
+// Every occurrence is claimed by exclusively creating a lock file in the directory.
+type FileLocker struct {
+	dir string
+}
+
+// NewFileLocker create a file locker in dir, the directory is created if not exists
+func NewFileLocker(dir string) (*FileLocker, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileLocker{dir: dir}, nil
+}
+
+// TryLock acquire the occurrence, return false if another process already acquired it
+func (l *FileLocker) TryLock(name string, scheduled time.Time) (bool, error) {
+	prefix := escapeFileName(name) + "."
+	path := filepath.Join(l.dir, prefix+strconv.FormatInt(scheduled.Unix(), 10)+".lock")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	_, err = fmt.Fprintf(f, "%d\n", os.Getpid())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+	l.cleanup(prefix, scheduled.Add(-lockRetention))
+	return true, nil
+}
+
+// cleanup remove lock files of the job scheduled before the given time
+func (l *FileLocker) cleanup(prefix string, before time.Time) {
+	matches, err := filepath.Glob(filepath.Join(l.dir, prefix+"*.lock"))
+	if err != nil {
+		return
+	}
+	for _, match := range matches {
+		ts := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), prefix), ".lock")
+		unix, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		if unix < before.Unix() {
+			_ = os.Remove(match)
+		}
+	}
+}
+
+func lockKey(name string, scheduled time.Time) string {
+	return name + "@" + strconv.FormatInt(scheduled.Unix(), 10)
+}
+
+// escapeFileName keep letters, digits, '-' and '_', other bytes are written as %XX
+func escapeFileName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		if ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_' {
+			b.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
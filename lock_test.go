@@ -0,0 +1,111 @@
+package gocronexpr
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryLocker_TryLock(t *testing.T) {
+	l := NewMemoryLocker()
+	scheduled := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		job       string
+		scheduled time.Time
+		want      bool
+	}{
+		{"first", "job", scheduled, true},
+		{"same_occurrence", "job", scheduled, false},
+		{"other_job", "other", scheduled, true},
+		{"next_occurrence", "job", scheduled.Add(time.Second), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.TryLock(tt.job, tt.scheduled)
+			if err != nil {
+				t.Errorf("MemoryLocker.TryLock() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MemoryLocker.TryLock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileLocker_TryLock(t *testing.T) {
+	dir := t.TempDir()
+	l1, err := NewFileLocker(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l2, _ := NewFileLocker(dir)
+	scheduled := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, err := l1.TryLock("a/b:c", scheduled); err != nil || !got {
+		t.Errorf("FileLocker.TryLock() = %v, %v, want true", got, err)
+	}
+	if got, err := l2.TryLock("a/b:c", scheduled); err != nil || got {
+		t.Errorf("FileLocker.TryLock() = %v, %v, want false", got, err)
+	}
+	// acquiring an occurrence far later removes the stale lock file
+	if got, err := l2.TryLock("a/b:c", scheduled.Add(48*time.Hour)); err != nil || !got {
+		t.Errorf("FileLocker.TryLock() = %v, %v, want true", got, err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.lock"))
+	if len(matches) != 1 {
+		t.Errorf("lock files = %v, want 1 file", matches)
+	}
+}
+
+func Test_scheduler_acquire(t *testing.T) {
+	daily, _ := New("0 0 0 * * *", time.UTC)
+	hourly, _ := New("0 0 * * * *", time.UTC)
+	options := &ScheduleOptions{Locker: NewMemoryLocker()}
+	scheduled := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// unnamed jobs of other schedules don't share the lock key
+	if err := (scheduler{daily}).acquire(options, scheduled); err != nil {
+		t.Errorf("scheduler.acquire() error = %v", err)
+	}
+	if err := (scheduler{hourly}).acquire(options, scheduled); err != nil {
+		t.Errorf("scheduler.acquire() error = %v", err)
+	}
+	if err := (scheduler{daily}).acquire(options, scheduled); err != ErrLocked {
+		t.Errorf("scheduler.acquire() error = %v, want %v", err, ErrLocked)
+	}
+}
+
+func TestRunSchedule_Locker(t *testing.T) {
+	// lock keys have a resolution of a second, the schedulers share one occurrence
+	schedule := soon(1)
+	locker := NewMemoryLocker()
+
+	var executed, locked int32
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RunSchedule(context.Background(), schedule, func(context.Context) error {
+				atomic.AddInt32(&executed, 1)
+				return nil
+			}, &ScheduleOptions{Name: "job", Locker: locker, Observer: ObserverFunc(func(event Event) {
+				if event.Type == EventSkipped && event.Err == ErrLocked {
+					atomic.AddInt32(&locked, 1)
+				}
+			})})
+		}()
+	}
+	wg.Wait()
+
+	// the occurrence is executed by one of the schedulers and skipped by the others
+	if executed != 1 || locked != 2 {
+		t.Errorf("executed %d times, locked %d times, want 1 and 2", executed, locked)
+	}
+}
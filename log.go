@@ -0,0 +1,77 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler drops all records, it's the default handler of the scheduler logger
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+var discardLogger = slog.New(discardHandler{})
+
+// logger of options with job and expression attributes
+func (s scheduler) logger(options *ScheduleOptions) *slog.Logger {
+	logger := options.Logger
+	if logger == nil {
+		logger = discardLogger
+	}
+	return logger.With("job", options.Name, "expression", s.schedule.String())
+}
+
+// logEvent write the event to the logger of options
+func (s scheduler) logEvent(options *ScheduleOptions, event Event) {
+	if options.Logger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	msg := "job " + event.Type.String()
+	switch event.Type {
+	case EventFailed:
+		level = slog.LevelError
+	case EventTimedOut, EventMisfired:
+		level = slog.LevelWarn
+	case EventSkipped:
+		if event.Err != ErrOverlap && event.Err != ErrLocked {
+			level = slog.LevelError
+			msg = "error acquire lock"
+		} else {
+			level = slog.LevelInfo
+		}
+	}
+	ctx := context.Background()
+	if !options.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{slog.Time("scheduled_at", event.Scheduled)}
+	if event.Attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", event.Attempt))
+	}
+	if event.Type == EventSucceeded || event.Type == EventFailed || event.Type == EventTimedOut {
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+	s.logger(options).LogAttrs(ctx, level, msg, attrs...)
+}
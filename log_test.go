@@ -0,0 +1,71 @@
+package gocronexpr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func Test_scheduler_logEvent(t *testing.T) {
+	c, err := New("0 * * * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled := time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		event     Event
+		wantLevel string
+		wantMsg   string
+		wantAttrs []string
+	}{
+		{"failed", Event{Type: EventFailed, Scheduled: scheduled, Attempt: 1, Duration: time.Second, Err: errors.New("fail")},
+			"ERROR", "job failed", []string{"job", "expression", "scheduled_at", "attempt", "duration", "error"}},
+		{"timed_out", Event{Type: EventTimedOut, Scheduled: scheduled, Attempt: 2, Err: ErrTimeout},
+			"WARN", "job timed_out", []string{"job", "expression", "scheduled_at", "attempt", "duration", "error"}},
+		{"overlap", Event{Type: EventSkipped, Scheduled: scheduled, Err: ErrOverlap},
+			"INFO", "job skipped", []string{"job", "expression", "scheduled_at", "error"}},
+		{"lock_error", Event{Type: EventSkipped, Scheduled: scheduled, Err: errors.New("disk full")},
+			"ERROR", "error acquire lock", []string{"job", "expression", "scheduled_at", "error"}},
+		{"scheduled", Event{Type: EventScheduled, Scheduled: scheduled},
+			"DEBUG", "job scheduled", []string{"job", "expression", "scheduled_at"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			scheduler{c}.logEvent(&ScheduleOptions{Name: "job", Logger: logger}, tt.event)
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("invalid log record %q: %v", buf.String(), err)
+			}
+			if record["level"] != tt.wantLevel || record["msg"] != tt.wantMsg {
+				t.Errorf("log level = %v, msg = %v, want %v, %v", record["level"], record["msg"], tt.wantLevel, tt.wantMsg)
+			}
+			for _, attr := range tt.wantAttrs {
+				if _, ok := record[attr]; !ok {
+					t.Errorf("log record %v missing attribute %v", record, attr)
+				}
+			}
+			if len(record) != len(tt.wantAttrs)+3 {
+				t.Errorf("log record %v has unexpected attributes", record)
+			}
+		})
+	}
+}
+
+func Test_scheduler_logger_discard(t *testing.T) {
+	c, err := New("0 * * * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (scheduler{c}).logger(&ScheduleOptions{}).Enabled(context.Background(), slog.LevelError) {
+		t.Errorf("default logger should discard all records")
+	}
+}
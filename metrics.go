@@ -0,0 +1,210 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets of the duration and lag histograms, in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// Metrics is an in-memory registry of job metrics, it's fed by ScheduleOptions.Metrics.
+// Metrics is also an Observer, so it can be used wherever an Observer is accepted.
+type Metrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	jobs    map[string]*JobMetrics
+}
+
+// JobMetrics of one job
+type JobMetrics struct {
+	Job string
+	// Runs is the number of attempts started
+	Runs uint64
+	// Failures is the number of attempts failed or timed out
+	Failures uint64
+	// Timeouts is the number of attempts timed out
+	Timeouts uint64
+	Skipped  uint64
+	Misfires uint64
+	// Duration of finished attempts
+	Duration Histogram
+	// Lag is the actual start time minus the scheduled time of first attempts, retries are not counted
+	Lag Histogram
+}
+
+// Histogram of durations in seconds
+type Histogram struct {
+	// Buckets are the upper bounds, the +Inf bucket is implicit
+	Buckets []float64
+	// Counts is the number of observations in each bucket, not cumulative, the last one is the +Inf bucket
+	Counts []uint64
+	Count  uint64
+	Sum    float64
+}
+
+// NewMetrics create a registry, buckets of histograms default to DefaultBuckets
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{buckets: buckets, jobs: make(map[string]*JobMetrics)}
+}
+
+// Observe update the metrics of the event's job
+func (m *Metrics) Observe(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[event.Job]
+	if !ok {
+		job = &JobMetrics{Job: event.Job, Duration: m.newHistogram(), Lag: m.newHistogram()}
+		m.jobs[event.Job] = job
+	}
+	switch event.Type {
+	case EventStarted:
+		job.Runs++
+		// the backoff of retries is not schedule lag
+		if event.Attempt == 1 {
+			job.Lag.observe(event.Started.Sub(event.Scheduled))
+		}
+	case EventSucceeded:
+		job.Duration.observe(event.Duration)
+	case EventFailed:
+		job.Failures++
+		job.Duration.observe(event.Duration)
+	case EventTimedOut:
+		job.Failures++
+		job.Timeouts++
+		job.Duration.observe(event.Duration)
+	case EventSkipped:
+		job.Skipped++
+	case EventMisfired:
+		job.Misfires++
+	}
+}
+
+// Snapshot copy the metrics of all jobs, sorted by job name
+func (m *Metrics) Snapshot() []JobMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]JobMetrics, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		s := *job
+		s.Duration = job.Duration.clone()
+		s.Lag = job.Lag.clone()
+		snapshot = append(snapshot, s)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Job < snapshot[j].Job
+	})
+	return snapshot
+}
+
+// Handler serve the metrics in Prometheus text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = m.WritePrometheus(w)
+	})
+}
+
+// WritePrometheus write the metrics in Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	jobs := m.Snapshot()
+	var b strings.Builder
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(job *JobMetrics) uint64
+	}{
+		{"gocronexpr_job_runs_total", "Number of job attempts started.", func(job *JobMetrics) uint64 { return job.Runs }},
+		{"gocronexpr_job_failures_total", "Number of job attempts failed or timed out.", func(job *JobMetrics) uint64 { return job.Failures }},
+		{"gocronexpr_job_timeouts_total", "Number of job attempts timed out.", func(job *JobMetrics) uint64 { return job.Timeouts }},
+		{"gocronexpr_job_skipped_total", "Number of occurrences skipped.", func(job *JobMetrics) uint64 { return job.Skipped }},
+		{"gocronexpr_job_misfires_total", "Number of occurrences misfired.", func(job *JobMetrics) uint64 { return job.Misfires }},
+	}
+	for _, counter := range counters {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", counter.name, counter.help, counter.name)
+		for i := range jobs {
+			fmt.Fprintf(&b, "%s{job=\"%s\"} %d\n", counter.name, escapeLabel(jobs[i].Job), counter.value(&jobs[i]))
+		}
+	}
+
+	histograms := []struct {
+		name  string
+		help  string
+		value func(job *JobMetrics) *Histogram
+	}{
+		{"gocronexpr_job_duration_seconds", "Duration of finished job attempts.", func(job *JobMetrics) *Histogram { return &job.Duration }},
+		{"gocronexpr_job_lag_seconds", "Actual start time minus scheduled time of the first job attempts.", func(job *JobMetrics) *Histogram { return &job.Lag }},
+	}
+	for _, histogram := range histograms {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s histogram\n", histogram.name, histogram.help, histogram.name)
+		for i := range jobs {
+			job := escapeLabel(jobs[i].Job)
+			h := histogram.value(&jobs[i])
+			var cumulative uint64
+			for j, count := range h.Counts {
+				cumulative += count
+				le := "+Inf"
+				if j < len(h.Buckets) {
+					le = strconv.FormatFloat(h.Buckets[j], 'g', -1, 64)
+				}
+				fmt.Fprintf(&b, "%s_bucket{job=\"%s\",le=\"%s\"} %d\n", histogram.name, job, le, cumulative)
+			}
+			fmt.Fprintf(&b, "%s_sum{job=\"%s\"} %s\n", histogram.name, job, strconv.FormatFloat(h.Sum, 'g', -1, 64))
+			fmt.Fprintf(&b, "%s_count{job=\"%s\"} %d\n", histogram.name, job, h.Count)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m *Metrics) newHistogram() Histogram {
+	return Histogram{Buckets: m.buckets, Counts: make([]uint64, len(m.buckets)+1)}
+}
+
+func (h *Histogram) observe(d time.Duration) {
+	v := d.Seconds()
+	i := sort.SearchFloat64s(h.Buckets, v)
+	h.Counts[i]++
+	h.Count++
+	h.Sum += v
+}
+
+func (h *Histogram) clone() Histogram {
+	c := *h
+	c.Counts = append([]uint64(nil), h.Counts...)
+	return c
+}
+
+// escapeLabel escape backslash, double-quote and line feed of a label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,82 @@
+package gocronexpr
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := NewMetrics(1, 10)
+	scheduled := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Type: EventStarted, Job: "a", Scheduled: scheduled, Started: scheduled.Add(500 * time.Millisecond), Attempt: 1},
+		{Type: EventFailed, Job: "a", Duration: 2 * time.Second, Err: errors.New("fail"), Attempt: 1},
+		// the retry after a backoff has no lag
+		{Type: EventStarted, Job: "a", Scheduled: scheduled, Started: scheduled.Add(5 * time.Second), Attempt: 2},
+		{Type: EventTimedOut, Job: "a", Duration: 20 * time.Second, Err: ErrTimeout, Attempt: 2},
+		{Type: EventStarted, Job: "a", Scheduled: scheduled.Add(time.Hour), Started: scheduled.Add(time.Hour + 5*time.Second), Attempt: 1},
+		{Type: EventSucceeded, Job: "a", Duration: 0, Attempt: 1},
+		{Type: EventSkipped, Job: "a", Err: ErrOverlap},
+		{Type: EventStarted, Job: "b", Scheduled: scheduled, Started: scheduled, Attempt: 1},
+		{Type: EventSucceeded, Job: "b", Duration: time.Second},
+		{Type: EventMisfired, Job: "b"},
+	}
+	for _, event := range events {
+		m.Observe(event)
+	}
+
+	snapshot := m.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].Job != "a" || snapshot[1].Job != "b" {
+		t.Fatalf("Metrics.Snapshot() = %+v, want jobs a and b", snapshot)
+	}
+	a, b := snapshot[0], snapshot[1]
+	if a.Runs != 3 || a.Failures != 2 || a.Timeouts != 1 || a.Skipped != 1 || a.Misfires != 0 {
+		t.Errorf("job a counters = %+v", a)
+	}
+	if b.Runs != 1 || b.Failures != 0 || b.Misfires != 1 {
+		t.Errorf("job b counters = %+v", b)
+	}
+	if got, want := a.Duration.Counts, []uint64{1, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("job a duration counts = %v, want %v", got, want)
+	}
+	if got, want := a.Lag.Counts, []uint64{1, 1, 0}; !slices.Equal(got, want) {
+		t.Errorf("job a lag counts = %v, want %v", got, want)
+	}
+	if a.Lag.Sum != 5.5 || a.Lag.Count != 2 {
+		t.Errorf("job a lag sum = %v, count = %v, want 5.5, 2", a.Lag.Sum, a.Lag.Count)
+	}
+	// an edge value falls into the bucket of its upper bound
+	if got, want := b.Duration.Counts, []uint64{1, 0, 0}; !slices.Equal(got, want) {
+		t.Errorf("job b duration counts = %v, want %v", got, want)
+	}
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := NewMetrics(1)
+	m.Observe(Event{Type: EventStarted, Job: `say "hi"`})
+	m.Observe(Event{Type: EventSucceeded, Job: `say "hi"`, Duration: 2 * time.Second})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %v", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE gocronexpr_job_runs_total counter\n",
+		`gocronexpr_job_runs_total{job="say \"hi\""} 1` + "\n",
+		"# TYPE gocronexpr_job_duration_seconds histogram\n",
+		`gocronexpr_job_duration_seconds_bucket{job="say \"hi\"",le="1"} 0` + "\n",
+		`gocronexpr_job_duration_seconds_bucket{job="say \"hi\"",le="+Inf"} 1` + "\n",
+		`gocronexpr_job_duration_seconds_sum{job="say \"hi\""} 2` + "\n",
+		`gocronexpr_job_duration_seconds_count{job="say \"hi\""} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+}
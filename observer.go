@@ -0,0 +1,111 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrOverlap is the error of a skipped event when the previous execution is still running, see ScheduleOptions.SkipOverlap
+	ErrOverlap = errors.New("previous execution is still running")
+	// ErrLocked is the error of a skipped event when the occurrence is acquired by another scheduler
+	ErrLocked = errors.New("occurrence is locked by another scheduler")
+)
+
+// EventType of the job lifecycle
+type EventType int
+
+// Job lifecycle events
+const (
+	// EventScheduled the next occurrence is calculated
+	EventScheduled EventType = iota
+	// EventStarted an attempt is started
+	EventStarted
+	// EventSucceeded an attempt returns without error
+	EventSucceeded
+	// EventFailed an attempt returns an error
+	EventFailed
+	// EventTimedOut an attempt does not finish within ScheduleOptions.Timeout
+	EventTimedOut
+	// EventSkipped the occurrence is not executed, Err is ErrOverlap, ErrLocked or the lock error
+	EventSkipped
+	// EventMisfired the occurrence is reached later than ScheduleOptions.MisfireThreshold and not executed
+	EventMisfired
+)
+
+var eventTypeNames = []string{"scheduled", "started", "succeeded", "failed", "timed_out", "skipped", "misfired"}
+
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "unknown"
+	}
+	return eventTypeNames[t]
+}
+
+// Event of the job lifecycle
+type Event struct {
+	Type EventType
+	// Job is ScheduleOptions.Name
+	Job string
+	// Scheduled time of the occurrence
+	Scheduled time.Time
+	// Started is the actual start time of the attempt, zero if not started
+	Started time.Time
+	// Duration of the attempt, only set for the outcome of an attempt
+	Duration time.Duration
+	// Attempt starts with 1, 0 if not started
+	Attempt int
+	Err     error
+}
+
+// Observer receives job lifecycle events, it's called synchronously by the scheduler.
+// It's called from two goroutines at the same time if ScheduleOptions.SkipOverlap is set.
+type Observer interface {
+	Observe(event Event)
+}
+
+// ObserverFunc adapts a function to Observer
+type ObserverFunc func(event Event)
+
+// Observe call f(event)
+func (f ObserverFunc) Observe(event Event) {
+	f(event)
+}
+
+// emit the event to the logger, the metrics and the observer of options
+func (s scheduler) emit(options *ScheduleOptions, event Event) {
+	event.Job = options.Name
+	s.logEvent(options, event)
+	if options.Metrics != nil {
+		options.Metrics.Observe(event)
+	}
+	if options.Observer != nil {
+		options.Observer.Observe(event)
+	}
+}
+
+// outcome event type of an attempt returning err
+func outcome(err error) EventType {
+	switch err {
+	case nil:
+		return EventSucceeded
+	case ErrTimeout:
+		return EventTimedOut
+	default:
+		return EventFailed
+	}
+}
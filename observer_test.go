@@ -0,0 +1,77 @@
+package gocronexpr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventType_String(t *testing.T) {
+	tests := []struct {
+		t    EventType
+		want string
+	}{
+		{EventScheduled, "scheduled"},
+		{EventTimedOut, "timed_out"},
+		{EventMisfired, "misfired"},
+		{EventType(100), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.t.String(); got != tt.want {
+			t.Errorf("EventType.String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func Test_outcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want EventType
+	}{
+		{nil, EventSucceeded},
+		{ErrTimeout, EventTimedOut},
+		{errors.New("fail"), EventFailed},
+	}
+	for _, tt := range tests {
+		if got := outcome(tt.err); got != tt.want {
+			t.Errorf("outcome(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRunSchedule_Observer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var events []Event
+	RunSchedule(ctx, everySchedule(time.Millisecond), func(context.Context) error {
+		time.Sleep(2 * time.Millisecond)
+		cancel()
+		return nil
+	}, &ScheduleOptions{
+		Name: "job",
+		Observer: ObserverFunc(func(event Event) {
+			events = append(events, event)
+		}),
+	})
+
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+		if event.Job != "job" || event.Scheduled.IsZero() {
+			t.Errorf("Event = %+v, want job and scheduled time", event)
+		}
+	}
+	// the following occurrence may be scheduled before the run is canceled
+	if want := []EventType{EventScheduled, EventStarted, EventSucceeded}; len(types) < 3 || !reflect.DeepEqual(types[:3], want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	if started := events[1]; started.Attempt != 1 || started.Started.Before(started.Scheduled) {
+		t.Errorf("started Event = %+v, want attempt 1 after the scheduled time", started)
+	}
+	if succeeded := events[2]; succeeded.Duration < 2*time.Millisecond {
+		t.Errorf("Event.Duration = %v, want at least 2ms", succeeded.Duration)
+	}
+}
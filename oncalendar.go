@@ -0,0 +1,475 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bits-and-blooms/bitset"
+)
+
+// calendarShorthands of systemd calendar events in the normalized form
+var calendarShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+}
+
+// calendarWeekdays of systemd starting from Sunday
+var calendarWeekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// maxCalendarYear is the latest year of calendar events
+const maxCalendarYear = 9999
+
+// yearRange of a calendar event, from..to/step
+type yearRange struct {
+	from int
+	to   int
+	step int
+}
+
+// Calendar is a schedule of a systemd calendar event like "Mon..Fri *-*-* 09:00:00", see systemd.time(7).
+// The weekdays and the date must both match, like the days of week and days of month of CronExpr.
+type Calendar struct {
+	text     string
+	location *time.Location
+
+	// years is nil for every year
+	years    []yearRange
+	months   *bitset.BitSet
+	days     *bitset.BitSet
+	weekdays *bitset.BitSet
+	hours    *bitset.BitSet
+	minutes  *bitset.BitSet
+	seconds  *bitset.BitSet
+	// fromEnd means days count from the end of the month, "~1" is the last day
+	fromEnd bool
+}
+
+// ParseOnCalendar parse the systemd calendar event, e.g. "Mon..Fri *-*-* 09:00:00", "*-*-01 00:00" or "daily".
+// Its location is the trailing timezone like "UTC" or "Asia/Shanghai", or location if there is none.
+func ParseOnCalendar(text string, location *time.Location) (*Calendar, error) {
+	c := &Calendar{
+		text:     strings.TrimSpace(text),
+		location: location,
+		months:   bitset.New(13),
+		days:     bitset.New(32),
+		weekdays: bitset.New(7),
+		hours:    bitset.New(24),
+		minutes:  bitset.New(60),
+		seconds:  bitset.New(60),
+	}
+	tokens := strings.Fields(text)
+	if n := len(tokens); n > 1 {
+		if l, err := time.LoadLocation(tokens[n-1]); err == nil && tokens[n-1] != "" && !strings.ContainsAny(tokens[n-1], ":*") {
+			c.location = l
+			tokens = tokens[:n-1]
+		}
+	}
+	if len(tokens) == 1 {
+		if normalized, ok := calendarShorthands[strings.ToLower(tokens[0])]; ok {
+			tokens = strings.Fields(normalized)
+		}
+	}
+
+	date, clock, weekdays := "*-*-*", "00:00:00", ""
+	for i, token := range tokens {
+		switch {
+		case strings.Contains(token, ":"):
+			clock = token
+		case strings.ContainsAny(token, "-~"):
+			date = token
+		case i == 0 && token != "":
+			weekdays = token
+		default:
+			return nil, fmt.Errorf("invalid calendar event \"%s\"", c.text)
+		}
+	}
+	if len(tokens) == 0 || len(tokens) > 3 {
+		return nil, fmt.Errorf("invalid calendar event \"%s\"", c.text)
+	}
+	if err := c.parseWeekdays(weekdays); err != nil {
+		return nil, err
+	}
+	if err := c.parseDate(date); err != nil {
+		return nil, err
+	}
+	if err := c.parseClock(clock); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Calendar) parseWeekdays(value string) error {
+	if value == "" {
+		setRange(c.weekdays, 0, 7)
+		return nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		from, to, isRange := strings.Cut(item, "..")
+		first, ok := calendarWeekday(from)
+		last := first
+		if isRange {
+			var ok2 bool
+			last, ok2 = calendarWeekday(to)
+			ok = ok && ok2
+			// Monday is the first day of week
+			if ok && (first+6)%7 > (last+6)%7 {
+				ok = false
+			}
+		}
+		if !ok {
+			return fmt.Errorf("invalid weekday '%s' in calendar event \"%s\"", item, c.text)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			c.weekdays.Set(uint(d))
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// calendarWeekday of an abbreviated or full English name
+func calendarWeekday(name string) (int, bool) {
+	for i, abbr := range calendarWeekdays {
+		if strings.EqualFold(name, abbr) || strings.EqualFold(name, time.Weekday(i).String()) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (c *Calendar) parseDate(value string) error {
+	sep := "-"
+	if strings.Contains(value, "~") {
+		sep, c.fromEnd = "~", true
+	}
+	// the day follows the last separator
+	i := strings.LastIndex(value, sep)
+	head, day := value[:i], value[i+1:]
+	parts := strings.Split(head, "-")
+	if len(parts) > 2 || sep == "~" && strings.Contains(day, "-") {
+		return fmt.Errorf("invalid date '%s' in calendar event \"%s\"", value, c.text)
+	}
+	month := parts[len(parts)-1]
+	if len(parts) == 2 {
+		if err := c.parseYears(parts[0]); err != nil {
+			return err
+		}
+	}
+	if err := c.parseComponent(c.months, month, 1, 12, "month"); err != nil {
+		return err
+	}
+	return c.parseComponent(c.days, day, 1, 31, "day")
+}
+
+func (c *Calendar) parseYears(value string) error {
+	if value == "*" {
+		return nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		r, err := c.parseRange(item, 1970, maxCalendarYear, "year")
+		if err != nil {
+			return err
+		}
+		c.years = append(c.years, r)
+	}
+	return nil
+}
+
+func (c *Calendar) parseClock(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) == 2 {
+		parts = append(parts, "00")
+	}
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid time '%s' in calendar event \"%s\"", value, c.text)
+	}
+	// fractions of a second are accepted only if they are zero, ".." is a range
+	seconds := strings.Split(parts[2], ",")
+	for i, item := range seconds {
+		if strings.Contains(item, "..") {
+			continue
+		}
+		if whole, fraction, ok := strings.Cut(item, "."); ok {
+			if strings.Trim(fraction, "0") != "" {
+				return fmt.Errorf("sub-second time '%s' is not supported in calendar event \"%s\"", value, c.text)
+			}
+			seconds[i] = whole
+		}
+	}
+	parts[2] = strings.Join(seconds, ",")
+	if err := c.parseComponent(c.hours, parts[0], 0, 23, "hour"); err != nil {
+		return err
+	}
+	if err := c.parseComponent(c.minutes, parts[1], 0, 59, "minute"); err != nil {
+		return err
+	}
+	return c.parseComponent(c.seconds, parts[2], 0, 59, "second")
+}
+
+// parseComponent of comma separated "*", "A", "A..B" and their repetitions like "A/S"
+func (c *Calendar) parseComponent(bits *bitset.BitSet, value string, min, max int, name string) error {
+	for _, item := range strings.Split(value, ",") {
+		r, err := c.parseRange(item, min, max, name)
+		if err != nil {
+			return err
+		}
+		for v := r.from; v <= r.to; v += r.step {
+			bits.Set(uint(v))
+		}
+	}
+	return nil
+}
+
+func (c *Calendar) parseRange(item string, min, max int, name string) (yearRange, error) {
+	r := yearRange{from: min, to: max, step: 1}
+	invalid := fmt.Errorf("invalid %s '%s' in calendar event \"%s\"", name, item, c.text)
+	span, step, hasStep := strings.Cut(item, "/")
+	if hasStep {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 {
+			return r, invalid
+		}
+		r.step = n
+	}
+	if span == "*" {
+		return r, nil
+	}
+	from, to, isRange := strings.Cut(span, "..")
+	var err error
+	if r.from, err = strconv.Atoi(from); err != nil {
+		return r, invalid
+	}
+	if isRange {
+		if r.to, err = strconv.Atoi(to); err != nil {
+			return r, invalid
+		}
+	} else if !hasStep {
+		r.to = r.from
+	}
+	if r.from < min || r.to > max || r.from > r.to {
+		return r, invalid
+	}
+	return r, nil
+}
+
+// String return the event as written
+func (c *Calendar) String() string {
+	return c.text
+}
+
+// Next time of the event after t, ErrExhausted is returned after its last year
+func (c *Calendar) Next(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	base = base.In(c.location)
+	// events of every year are searched for 400 years, a full cycle of the Gregorian calendar
+	last, exhausted := base.Year()+400, error(nil)
+	if c.years != nil {
+		last, exhausted = 0, ErrExhausted
+		for _, r := range c.years {
+			last = max(last, r.to)
+		}
+	}
+
+	day := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, c.location)
+	for day.Year() <= last {
+		switch {
+		case !c.matchYear(day.Year()):
+			day = time.Date(day.Year()+1, 1, 1, 0, 0, 0, 0, c.location)
+		case !c.months.Test(uint(day.Month())):
+			day = time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, c.location)
+		default:
+			if c.matchDay(day) {
+				if next, ok := c.nextOnDay(day, base); ok {
+					return next, nil
+				}
+			}
+			day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.location)
+		}
+	}
+	if exhausted != nil {
+		return time.Time{}, exhausted
+	}
+	return time.Time{}, fmt.Errorf("calendar event \"%s\" never occurs", c.text)
+}
+
+// Prev time of the event before t, ErrExhausted is returned before its first year
+func (c *Calendar) Prev(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	base = base.In(c.location)
+	first, exhausted := base.Year()-400, error(nil)
+	if c.years != nil {
+		first, exhausted = maxCalendarYear, ErrExhausted
+		for _, r := range c.years {
+			first = min(first, r.from)
+		}
+	}
+
+	day := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, c.location)
+	for day.Year() >= first {
+		switch {
+		case !c.matchYear(day.Year()):
+			day = time.Date(day.Year(), 1, 0, 0, 0, 0, 0, c.location)
+		case !c.months.Test(uint(day.Month())):
+			day = time.Date(day.Year(), day.Month(), 0, 0, 0, 0, 0, c.location)
+		default:
+			if c.matchDay(day) {
+				if prev, ok := c.prevOnDay(day, base); ok {
+					return prev, nil
+				}
+			}
+			day = time.Date(day.Year(), day.Month(), day.Day()-1, 0, 0, 0, 0, c.location)
+		}
+	}
+	if exhausted != nil {
+		return time.Time{}, exhausted
+	}
+	return time.Time{}, fmt.Errorf("calendar event \"%s\" never occurs", c.text)
+}
+
+func (c *Calendar) matchYear(year int) bool {
+	if c.years == nil {
+		return true
+	}
+	for _, r := range c.years {
+		if year >= r.from && year <= r.to && (year-r.from)%r.step == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Calendar) matchDay(day time.Time) bool {
+	if !c.weekdays.Test(uint(day.Weekday())) {
+		return false
+	}
+	d := day.Day()
+	if c.fromEnd {
+		d = time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day() - d + 1
+	}
+	return c.days.Test(uint(d))
+}
+
+// nextOnDay return the first time of the day after base
+func (c *Calendar) nextOnDay(day time.Time, base time.Time) (time.Time, bool) {
+	for h, ok := c.hours.NextSet(0); ok; h, ok = c.hours.NextSet(h + 1) {
+		for m, ok := c.minutes.NextSet(0); ok; m, ok = c.minutes.NextSet(m + 1) {
+			for s, ok := c.seconds.NextSet(0); ok; s, ok = c.seconds.NextSet(s + 1) {
+				next := time.Date(day.Year(), day.Month(), day.Day(), int(h), int(m), int(s), 0, c.location)
+				if next.After(base) {
+					return next, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// prevOnDay return the last time of the day before base
+func (c *Calendar) prevOnDay(day time.Time, base time.Time) (time.Time, bool) {
+	for h, ok := prevSet(c.hours, 23); ok; h, ok = prevSet(c.hours, h-1) {
+		for m, ok := prevSet(c.minutes, 59); ok; m, ok = prevSet(c.minutes, m-1) {
+			for s, ok := prevSet(c.seconds, 59); ok; s, ok = prevSet(c.seconds, s-1) {
+				prev := time.Date(day.Year(), day.Month(), day.Day(), h, m, s, 0, c.location)
+				if prev.Before(base) {
+					return prev, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// OnCalendar convert the expression to a systemd calendar event like "Mon..Fri *-*-* 09:00:00".
+// The timezone is appended unless the location is time.Local, ErrNotRepresentable is returned if it has no IANA name.
+func (c *CronExpr) OnCalendar() (string, error) {
+	two := func(v int) string {
+		return fmt.Sprintf("%02d", v)
+	}
+	month := func(v int) string {
+		return two(v + 1)
+	}
+	var parts []string
+	if p := analyze(c.daysOfWeek, 0, 6); p.kind != patternAll {
+		if p.kind == patternStep {
+			p = expandStep(p, 6)
+		}
+		var items []string
+		for _, s := range p.spans {
+			// systemd ranges run from Monday to Sunday
+			if s.from == 0 && s.to > 0 {
+				items = append(items, calendarWeekdays[0])
+				s.from = 1
+			}
+			items = append(items, calendarWeekdays[s.from])
+			if s.to > s.from {
+				items[len(items)-1] += ".." + calendarWeekdays[s.to]
+			}
+		}
+		parts = append(parts, strings.Join(items, ","))
+	}
+	parts = append(parts, "*-"+calendarField(analyze(c.months, 0, 11), month)+"-"+
+		calendarField(analyze(c.daysOfMonth, 1, 31), two))
+	parts = append(parts, calendarField(analyze(c.hours, 0, 23), two)+":"+
+		calendarField(analyze(c.minutes, 0, 59), two)+":"+calendarField(analyze(c.seconds, 0, 59), two))
+
+	switch name := c.location.String(); {
+	case c.location == time.Local:
+	case c.location == time.UTC:
+		parts = append(parts, "UTC")
+	default:
+		if _, err := time.LoadLocation(name); err != nil {
+			return "", fmt.Errorf("%w: location %s has no IANA name", ErrNotRepresentable, name)
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// calendarField write the pattern with systemd ranges "A..B" and repetitions "A/S"
+func calendarField(p fieldPattern, name func(int) string) string {
+	switch p.kind {
+	case patternAll:
+		return "*"
+	case patternStep:
+		return name(p.start) + "/" + strconv.Itoa(p.step)
+	}
+	items := make([]string, len(p.spans))
+	for i, s := range p.spans {
+		items[i] = name(s.from)
+		if s.to > s.from {
+			items[i] += ".." + name(s.to)
+		}
+	}
+	return strings.Join(items, ",")
+}
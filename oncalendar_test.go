@@ -0,0 +1,169 @@
+package gocronexpr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCalendar_Next(t *testing.T) {
+	// 2020-01-01 is a Wednesday
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Mon..Fri *-*-* 09:00:00", []string{"2020-01-01 09:00", "2020-01-02 09:00", "2020-01-03 09:00", "2020-01-06 09:00"}},
+		{"Sat,Sun 10:00", []string{"2020-01-04 10:00", "2020-01-05 10:00", "2020-01-11 10:00"}},
+		{"*-*-01 00:00", []string{"2020-02-01 00:00", "2020-03-01 00:00"}},
+		{"daily", []string{"2020-01-02 00:00", "2020-01-03 00:00"}},
+		{"weekly", []string{"2020-01-06 00:00", "2020-01-13 00:00"}},
+		{"quarterly", []string{"2020-04-01 00:00", "2020-07-01 00:00"}},
+		{"*:0/15", []string{"2020-01-01 00:15", "2020-01-01 00:30", "2020-01-01 00:45", "2020-01-01 01:00"}},
+		{"*-*-* 08..09:00/30", []string{"2020-01-01 08:00", "2020-01-01 08:30", "2020-01-01 09:00", "2020-01-01 09:30"}},
+		{"*-02~01 12:00", []string{"2020-02-29 12:00", "2021-02-28 12:00"}},
+		{"Fri *-*-13", []string{"2020-03-13 00:00", "2020-11-13 00:00"}},
+		{"2021/2-06-01", []string{"2021-06-01 00:00", "2023-06-01 00:00"}},
+		{"2020,2021-12-25 18:00 UTC", []string{"2020-12-25 18:00", "2021-12-25 18:00"}},
+		{"*-*-* 00:00:05.000", []string{"2020-01-01 00:00", "2020-01-02 00:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			c, err := ParseOnCalendar(tt.text, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := occurrences(t, c, from, len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Calendar.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_Prev(t *testing.T) {
+	// 2020-01-01 is a Wednesday
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Mon..Fri *-*-* 09:00:00", "2019-12-31 09:00:00"},
+		{"Sat,Sun 10:00", "2019-12-29 10:00:00"},
+		{"weekly", "2019-12-30 00:00:00"},
+		{"quarterly", "2019-10-01 00:00:00"},
+		{"*:0/15", "2019-12-31 23:45:00"},
+		{"*-02~01 12:00", "2019-02-28 12:00:00"},
+		{"Fri *-*-13", "2019-12-13 00:00:00"},
+		{"*-*-* 00:00:05.000", "2019-12-31 00:00:05"},
+		{"2017/2-06-01", "2019-06-01 00:00:00"},
+		{"2021/2-06-01", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			c, err := ParseOnCalendar(tt.text, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.Prev(&from)
+			if tt.want == "" {
+				if !errors.Is(err, ErrExhausted) {
+					t.Errorf("Calendar.Prev() = %v, %v, want ErrExhausted", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Format("2006-01-02 15:04:05") != tt.want {
+				t.Errorf("Calendar.Prev() = %v, want %v", got.Format("2006-01-02 15:04:05"), tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_Next_location(t *testing.T) {
+	c, err := ParseOnCalendar("*-*-* 08:00 Asia/Shanghai", time.UTC)
+	if err != nil {
+		t.Skip(err)
+	}
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := c.Next(&base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(24 * time.Hour); !next.Equal(want) {
+		t.Errorf("Calendar.Next() = %v, want %v", next, want)
+	}
+}
+
+func TestCalendar_Next_end(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, _ := ParseOnCalendar("2019-01-01", time.UTC)
+	if _, err := c.Next(&base); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Calendar.Next() error = %v, want ErrExhausted", err)
+	}
+	c, _ = ParseOnCalendar("*-02-30", time.UTC)
+	if _, err := c.Next(&base); err == nil || errors.Is(err, ErrExhausted) {
+		t.Errorf("Calendar.Next() error = %v, want never occurs", err)
+	}
+}
+
+func TestParseOnCalendar_errors(t *testing.T) {
+	for _, text := range []string{"", "Funday", "Fri..Mon", "*-13-01", "*-*-* 25:00", "*-*-* 12:00:00.5", "1969-01-01",
+		"Mon *-*-* 00:00 Mars/Olympus", "*-*-*-* 00:00", "Mon *-*-* 00:00 00:00", "*:*/0"} {
+		if _, err := ParseOnCalendar(text, time.UTC); err == nil {
+			t.Errorf("ParseOnCalendar(%q) error = nil", text)
+		}
+	}
+}
+
+func TestCronExpr_OnCalendar(t *testing.T) {
+	tests := []struct {
+		expression string
+		location   *time.Location
+		want       string
+	}{
+		{"0 0 9 * * MON-FRI", time.Local, "Mon..Fri *-*-* 09:00:00"},
+		{"0 */15 * * * *", time.UTC, "*-*-* *:00/15:00 UTC"},
+		{"0 30 2 1 * *", time.UTC, "*-*-01 02:30:00 UTC"},
+		{"0 0 0 25 12 *", time.UTC, "*-12-25 00:00:00 UTC"},
+		{"0 0 12 * * SUN-TUE,SAT", time.UTC, "Sun,Mon..Tue,Sat *-*-* 12:00:00 UTC"},
+		{"0 0 8-18/2 * 1,4,7,10 *", time.UTC, "*-01/3-* 08,10,12,14,16,18:00:00 UTC"},
+		{"0 0 0 1-7 * MON", time.UTC, "Mon *-*-01..07 00:00:00 UTC"},
+		{"10-20 0 9 * * *", time.UTC, "*-*-* 09:00:10..20 UTC"},
+		{"5,10-20,30 */30 * * * *", time.UTC, "*-*-* *:00/30:05,10..20,30 UTC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, _ := New(tt.expression, tt.location)
+			got, err := c.OnCalendar()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CronExpr.OnCalendar() = %v, want %v", got, tt.want)
+			}
+
+			// parsed back, the event fires at the same times
+			event, err := ParseOnCalendar(got, tt.location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			base := time.Date(2020, 1, 1, 0, 0, 0, 0, tt.location)
+			for i := 0; i < 40; i++ {
+				want, _ := c.Next(&base)
+				got, err := event.Next(&base)
+				if err != nil || !got.Equal(want) {
+					t.Fatalf("Calendar.Next(%v) = %v, %v, want %v", base, got, err, want)
+				}
+				base = want
+			}
+		})
+	}
+
+	c, _ := New("0 0 9 * * *", time.FixedZone("X", 3600))
+	if _, err := c.OnCalendar(); !errors.Is(err, ErrNotRepresentable) {
+		t.Errorf("CronExpr.OnCalendar() error = %v, want ErrNotRepresentable", err)
+	}
+}
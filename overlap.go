@@ -0,0 +1,194 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"time"
+)
+
+// OverlapOptions of FindOverlaps
+type OverlapOptions struct {
+	// From and To is the horizon, both inclusive
+	From time.Time
+	To   time.Time
+	// Window reports fires within the window of each other as near, 0 reports coincidences only
+	Window time.Duration
+	// Limit of coincidences and near fires reported of each pair, 0 means no limit
+	Limit int
+}
+
+// Overlap of a pair of expressions in the horizon
+type Overlap struct {
+	// A and B are indexes of the expressions, A < B
+	A int
+	B int
+	// Coincidences are the times both fire
+	Coincidences []time.Time
+	// Near are fires within the window of each other which do not coincide.
+	// Each fire is paired with the latest earlier fire of the other expression.
+	Near []NearFire
+	// MinSeparation between the fires of A and B, MinA and MinB are the closest fires
+	MinSeparation time.Duration
+	MinA          time.Time
+	MinB          time.Time
+}
+
+// NearFire is a pair of fires within the window
+type NearFire struct {
+	A time.Time
+	B time.Time
+}
+
+// Separation of the fires
+func (n NearFire) Separation() time.Duration {
+	return absDuration(n.A.Sub(n.B))
+}
+
+// FindOverlaps analyze each pair of expressions in the horizon, pairs of which either one never fires are omitted.
+// Fires are enumerated pair by pair, and the fires far from both neighbours of the other expression are skipped
+// over by a single Prev and Next, so a dense schedule is not visited fire by fire between the fires of a sparse one.
+func FindOverlaps(exprs []*CronExpr, options OverlapOptions) ([]Overlap, error) {
+	var overlaps []Overlap
+	for i := 0; i < len(exprs); i++ {
+		for j := i + 1; j < len(exprs); j++ {
+			overlap, found, err := findOverlap(exprs[i], exprs[j], options)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				overlap.A, overlap.B = i, j
+				overlaps = append(overlaps, overlap)
+			}
+		}
+	}
+	return overlaps, nil
+}
+
+// cursor walks the fires of an expression in the horizon
+type cursor struct {
+	expr *CronExpr
+	// at is the current fire, zero if no more fires
+	at time.Time
+	// last is the previous fire, zero if none
+	last time.Time
+}
+
+// seek move to the first fire after t, or zero if it's after the horizon
+func (c *cursor) seek(t time.Time, to time.Time) error {
+	next, err := c.expr.Next(&t)
+	if err != nil {
+		return err
+	}
+	if next.After(to) {
+		next = time.Time{}
+	}
+	c.at = next
+	return nil
+}
+
+func findOverlap(a, b *CronExpr, options OverlapOptions) (Overlap, bool, error) {
+	overlap := Overlap{MinSeparation: -1}
+	start := options.From.Add(-time.Second)
+	ca, cb := &cursor{expr: a}, &cursor{expr: b}
+	if err := ca.seek(start, options.To); err != nil {
+		return overlap, false, err
+	}
+	if err := cb.seek(start, options.To); err != nil {
+		return overlap, false, err
+	}
+	if ca.at.IsZero() || cb.at.IsZero() {
+		return overlap, false, nil
+	}
+
+	closest := func(atA, atB time.Time) {
+		if separation := absDuration(atA.Sub(atB)); overlap.MinSeparation < 0 || separation < overlap.MinSeparation {
+			overlap.MinSeparation, overlap.MinA, overlap.MinB = separation, atA, atB
+		}
+	}
+	report := func(atA, atB time.Time) {
+		if atA.IsZero() || atB.IsZero() {
+			return
+		}
+		closest(atA, atB)
+		separation := absDuration(atA.Sub(atB))
+		if separation > options.Window || options.Limit > 0 && len(overlap.Coincidences)+len(overlap.Near) >= options.Limit {
+			return
+		}
+		if separation == 0 {
+			overlap.Coincidences = append(overlap.Coincidences, atA)
+		} else {
+			overlap.Near = append(overlap.Near, NearFire{A: atA, B: atB})
+		}
+	}
+
+	for !ca.at.IsZero() || !cb.at.IsZero() {
+		// visit the earlier fire, the fire of a goes first when they coincide
+		cur, other, fromA := ca, cb, true
+		if ca.at.IsZero() || !cb.at.IsZero() && cb.at.Before(ca.at) {
+			cur, other, fromA = cb, ca, false
+		}
+		if fromA {
+			report(cur.at, other.last)
+		} else {
+			report(other.last, cur.at)
+		}
+		if other.at.IsZero() && cur.at.Sub(other.last) > options.Window {
+			// the later fires are even farther from the last fire of the other expression
+			break
+		}
+		cur.last = cur.at
+
+		// fires far from both the last fire and the next fire of the other expression can't be the closest
+		// or be reported, skip to the first one close enough to the next fire
+		base := cur.at
+		reach := options.Window
+		if overlap.MinSeparation > reach {
+			reach = overlap.MinSeparation
+		}
+		if !other.at.IsZero() && other.at.Sub(cur.at) > reach {
+			// the latest fire before the next fire of the other expression is the closest of the skipped ones
+			prev, err := cur.expr.Prev(&other.at)
+			if err != nil {
+				return overlap, false, err
+			}
+			if prev.After(cur.at) {
+				if fromA {
+					closest(prev, other.at)
+				} else {
+					closest(other.at, prev)
+				}
+				if overlap.MinSeparation > reach {
+					reach = overlap.MinSeparation
+				}
+			}
+			if other.last.IsZero() || cur.at.Sub(other.last) > reach {
+				if skip := other.at.Add(-reach - time.Second); skip.After(base) {
+					base = skip
+				}
+			}
+		}
+		if err := cur.seek(base, options.To); err != nil {
+			return overlap, false, err
+		}
+	}
+	return overlap, true, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,163 @@
+package gocronexpr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// bruteForceOverlap enumerate every fire of both expressions
+func bruteForceOverlap(t *testing.T, a, b *CronExpr, options OverlapOptions) Overlap {
+	fires := func(c *CronExpr) []time.Time {
+		var result []time.Time
+		base := options.From.Add(-time.Second)
+		for {
+			next, err := c.Next(&base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.After(options.To) {
+				return result
+			}
+			result = append(result, next)
+			base = next
+		}
+	}
+	// latest fire of times before t, or at t if inclusive
+	latest := func(times []time.Time, t time.Time, inclusive bool) (time.Time, bool) {
+		var found time.Time
+		for _, x := range times {
+			if x.Before(t) || inclusive && x.Equal(t) {
+				found = x
+			}
+		}
+		return found, !found.IsZero()
+	}
+
+	fa, fb := fires(a), fires(b)
+	overlap := Overlap{MinSeparation: -1}
+	for _, x := range fa {
+		for _, y := range fb {
+			if d := absDuration(x.Sub(y)); overlap.MinSeparation < 0 || d < overlap.MinSeparation {
+				overlap.MinSeparation = d
+			}
+		}
+	}
+	// merge both in time order, a goes first when they coincide
+	i, j := 0, 0
+	for i < len(fa) || j < len(fb) {
+		if j >= len(fb) || i < len(fa) && !fb[j].Before(fa[i]) {
+			if y, ok := latest(fb, fa[i], false); ok && fa[i].Sub(y) <= options.Window {
+				overlap.Near = append(overlap.Near, NearFire{A: fa[i], B: y})
+			}
+			i++
+		} else {
+			if x, ok := latest(fa, fb[j], true); ok && fb[j].Sub(x) <= options.Window {
+				if x.Equal(fb[j]) {
+					overlap.Coincidences = append(overlap.Coincidences, x)
+				} else {
+					overlap.Near = append(overlap.Near, NearFire{A: x, B: fb[j]})
+				}
+			}
+			j++
+		}
+	}
+	return overlap
+}
+
+func TestFindOverlaps(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	pairs := []struct {
+		a, b   string
+		window time.Duration
+	}{
+		{"0 0 * * * *", "0 30 * * * *", 0},
+		{"0 0 * * * *", "0 */20 * * * *", 0},
+		{"0 0 * * * *", "0 */20 * * * *", 20 * time.Minute},
+		{"0 0 3 * * *", "0 */7 * * * *", 5 * time.Minute},
+		{"0 0 3 * * *", "0 58 2,14 * * *", 0},
+		{"0 0 3 * * *", "0 58 2,14 * * *", 5 * time.Minute},
+		{"*/30 * 1 * * *", "0 0 * * * *", time.Minute},
+		{"0 0 12 * * THU", "0 0 0 * * *", time.Hour},
+		{"0 0 12 * * THU", "0 0 12 * * *", 0},
+		{"0 5 * * * *", "0 0 0 1 1 *", 10 * time.Minute},
+		{"*/10 * * * * *", "5 0 12 * * THU", time.Minute},
+		{"0 */7 * * * *", "30 0 13 * * *", 0},
+	}
+	for _, tt := range pairs {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, _ := New(tt.a, time.UTC)
+			b, _ := New(tt.b, time.UTC)
+			options := OverlapOptions{From: from, To: to, Window: tt.window}
+			overlaps, err := FindOverlaps([]*CronExpr{a, b}, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(overlaps) != 1 {
+				t.Fatalf("FindOverlaps() = %v, want one pair", overlaps)
+			}
+			got, want := overlaps[0], bruteForceOverlap(t, a, b, options)
+			if got.MinSeparation != want.MinSeparation {
+				t.Errorf("MinSeparation = %v, want %v", got.MinSeparation, want.MinSeparation)
+			}
+			if got.MinSeparation != absDuration(got.MinA.Sub(got.MinB)) {
+				t.Errorf("MinA = %v, MinB = %v, want separation %v", got.MinA, got.MinB, got.MinSeparation)
+			}
+			if !reflect.DeepEqual(got.Coincidences, want.Coincidences) {
+				t.Errorf("Coincidences = %v, want %v", got.Coincidences, want.Coincidences)
+			}
+			if !reflect.DeepEqual(got.Near, want.Near) {
+				t.Errorf("Near = %v, want %v", got.Near, want.Near)
+			}
+		})
+	}
+}
+
+// A dense schedule is skipped between the fires of a sparse one instead of visited each second
+func TestFindOverlaps_dense(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	dense, _ := New("* * * * * *", time.UTC)
+	sparse, _ := New("0 0 12 1 7 *", time.UTC)
+	start := time.Now()
+	overlaps, err := FindOverlaps([]*CronExpr{dense, sparse}, OverlapOptions{From: from, To: from.AddDate(1, 0, 0), Window: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FindOverlaps() took %v", elapsed)
+	}
+	fire := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	want := Overlap{
+		A:             0,
+		B:             1,
+		Coincidences:  []time.Time{fire},
+		Near:          []NearFire{{A: fire.Add(time.Second), B: fire}},
+		MinSeparation: 0,
+		MinA:          fire,
+		MinB:          fire,
+	}
+	if len(overlaps) != 1 || !reflect.DeepEqual(overlaps[0], want) {
+		t.Errorf("FindOverlaps() = %+v, want %+v", overlaps, want)
+	}
+}
+
+func TestFindOverlaps_pairs(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var exprs []*CronExpr
+	for _, expression := range []string{"0 0 * * * *", "0 0 0 1 1 *", "0 0 */2 * * *"} {
+		c, _ := New(expression, time.UTC)
+		exprs = append(exprs, c)
+	}
+	overlaps, err := FindOverlaps(exprs, OverlapOptions{From: from.Add(time.Hour), To: from.Add(24 * time.Hour), Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the yearly expression never fires in the horizon
+	if len(overlaps) != 1 || overlaps[0].A != 0 || overlaps[0].B != 2 {
+		t.Fatalf("FindOverlaps() = %+v, want pair 0 and 2", overlaps)
+	}
+	if len(overlaps[0].Coincidences) != 3 || overlaps[0].MinSeparation != 0 {
+		t.Errorf("FindOverlaps() = %+v, want 3 coincidences", overlaps[0])
+	}
+}
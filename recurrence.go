@@ -0,0 +1,695 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// frequency of a recurrence rule, from the finest
+type frequency int
+
+const (
+	secondly frequency = iota
+	minutely
+	hourly
+	daily
+	weekly
+	monthly
+	yearly
+)
+
+var frequencies = map[string]frequency{
+	"SECONDLY": secondly,
+	"MINUTELY": minutely,
+	"HOURLY":   hourly,
+	"DAILY":    daily,
+	"WEEKLY":   weekly,
+	"MONTHLY":  monthly,
+	"YEARLY":   yearly,
+}
+
+// weekdayNum is a BYDAY value like "MO" or "-1FR", n is 0 for every such weekday
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+var weekdayNumPattern = regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)$`)
+
+// Recurrence is a schedule of an iCalendar recurrence rule of RFC 5545
+type Recurrence struct {
+	text     string
+	location *time.Location
+	// start is DTSTART, wall is its wall clock in UTC so the arithmetic of periods ignores DST
+	start time.Time
+	wall  time.Time
+
+	freq       frequency
+	interval   int
+	count      int
+	until      time.Time
+	byMonth    []int
+	byMonthDay []int
+	byDay      []weekdayNum
+	byHour     []int
+	byMinute   []int
+	bySecond   []int
+	bySetPos   []int
+	wkst       time.Weekday
+
+	// exdates are excluded times, exdays are excluded dates in wall clock
+	exdates []time.Time
+	exdays  []time.Time
+}
+
+// ParseRRule parse the DTSTART, RRULE and EXDATE properties separated by whitespace, e.g.
+// "DTSTART;TZID=Europe/Berlin:20200106T090000 RRULE:FREQ=MONTHLY;BYDAY=-1FR".
+// Times without TZID and not in UTC are in location. BYYEARDAY, BYWEEKNO and RDATE are not supported.
+func ParseRRule(text string, location *time.Location) (*Recurrence, error) {
+	r := &Recurrence{text: strings.TrimSpace(text), interval: 1, wkst: time.Monday}
+	var rule string
+	for _, property := range strings.Fields(text) {
+		name, value, ok := strings.Cut(property, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid property '%s' in rrule \"%s\"", property, r.text)
+		}
+		name, params, _ := strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "DTSTART":
+			times, _, err := parseICalTimes(value, params, location)
+			if err != nil || len(times) != 1 || !r.start.IsZero() {
+				return nil, fmt.Errorf("invalid DTSTART '%s' in rrule \"%s\"", property, r.text)
+			}
+			r.start = times[0]
+		case "RRULE":
+			if rule != "" {
+				return nil, fmt.Errorf("multiple RRULE in rrule \"%s\"", r.text)
+			}
+			rule = value
+		case "EXDATE":
+			times, dateOnly, err := parseICalTimes(value, params, location)
+			if err != nil {
+				return nil, fmt.Errorf("invalid EXDATE '%s' in rrule \"%s\"", property, r.text)
+			}
+			if dateOnly {
+				for _, t := range times {
+					r.exdays = append(r.exdays, wallClock(t))
+				}
+			} else {
+				r.exdates = append(r.exdates, times...)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported property '%s' in rrule \"%s\"", name, r.text)
+		}
+	}
+	if r.start.IsZero() {
+		return nil, fmt.Errorf("missing DTSTART in rrule \"%s\"", r.text)
+	}
+	if rule == "" {
+		return nil, fmt.Errorf("missing RRULE in rrule \"%s\"", r.text)
+	}
+	r.location = r.start.Location()
+	r.wall = wallClock(r.start)
+	if err := r.parseRule(rule); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Recurrence) parseRule(rule string) error {
+	hasFreq := false
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		value = strings.ToUpper(value)
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq, hasFreq = frequencies[value]
+			if !hasFreq {
+				err = fmt.Errorf("unknown")
+			}
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("less than 1")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err == nil && r.count < 1 {
+				err = fmt.Errorf("less than 1")
+			}
+		case "UNTIL":
+			var times []time.Time
+			var dateOnly bool
+			times, dateOnly, err = parseICalTimes(value, "", r.location)
+			if err == nil && len(times) == 1 {
+				r.until = times[0]
+				if dateOnly {
+					r.until = r.until.AddDate(0, 0, 1).Add(-time.Second)
+				}
+			} else if err == nil {
+				err = fmt.Errorf("not a single time")
+			}
+		case "BYMONTH":
+			r.byMonth, err = parseInts(value, 1, 12, false)
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseInts(value, 1, 31, true)
+		case "BYHOUR":
+			r.byHour, err = parseInts(value, 0, 23, false)
+		case "BYMINUTE":
+			r.byMinute, err = parseInts(value, 0, 59, false)
+		case "BYSECOND":
+			r.bySecond, err = parseInts(value, 0, 59, false)
+		case "BYSETPOS":
+			r.bySetPos, err = parseInts(value, 1, 366, true)
+		case "BYDAY":
+			r.byDay, err = parseWeekdayNums(value)
+		case "WKST":
+			var days []weekdayNum
+			days, err = parseWeekdayNums(value)
+			if err == nil && (len(days) != 1 || days[0].n != 0) {
+				err = fmt.Errorf("not a weekday")
+			}
+			if err == nil {
+				r.wkst = days[0].day
+			}
+		default:
+			return fmt.Errorf("unsupported rule part '%s' in rrule \"%s\"", key, r.text)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s '%s' in rrule \"%s\": %w", key, value, r.text, err)
+		}
+	}
+
+	switch {
+	case !hasFreq:
+		return fmt.Errorf("missing FREQ in rrule \"%s\"", r.text)
+	case r.count > 0 && !r.until.IsZero():
+		return fmt.Errorf("both COUNT and UNTIL in rrule \"%s\"", r.text)
+	case r.freq == weekly && len(r.byMonthDay) > 0:
+		return fmt.Errorf("BYMONTHDAY with FREQ=WEEKLY in rrule \"%s\"", r.text)
+	case len(r.bySetPos) > 0 && len(r.byMonth)+len(r.byMonthDay)+len(r.byDay)+len(r.byHour)+len(r.byMinute)+len(r.bySecond) == 0:
+		return fmt.Errorf("BYSETPOS without other BY parts in rrule \"%s\"", r.text)
+	}
+	for _, d := range r.byDay {
+		if d.n != 0 && r.freq != monthly && r.freq != yearly {
+			return fmt.Errorf("BYDAY with ordinals requires FREQ=MONTHLY or FREQ=YEARLY in rrule \"%s\"", r.text)
+		}
+	}
+	return nil
+}
+
+// String return the rule as written
+func (r *Recurrence) String() string {
+	return r.text
+}
+
+// Next occurrence after t, ErrExhausted is returned after the last occurrence of COUNT or UNTIL
+func (r *Recurrence) Next(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	// DTSTART is always the first occurrence
+	if r.start.After(base) && !r.excluded(r.start) {
+		return r.start, nil
+	}
+
+	n := 0
+	if r.count == 0 {
+		n = r.periodIndex(wallClock(base.In(r.location)))
+	}
+	var next time.Time
+	found, err := r.scan(n, wallClock(base.In(r.location)).AddDate(400, 0, 0), func(o time.Time) bool {
+		if o.After(base) && !r.excluded(o) {
+			next = o
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !found {
+		return time.Time{}, fmt.Errorf("rrule \"%s\" led to runaway search for next occurrence", r.text)
+	}
+	return next, nil
+}
+
+// Prev occurrence before t, ErrExhausted is returned before DTSTART
+func (r *Recurrence) Prev(t *time.Time) (time.Time, error) {
+	base := time.Now()
+	if t != nil {
+		base = *t
+	}
+	if !r.start.Before(base) {
+		return time.Time{}, ErrExhausted
+	}
+
+	var prev time.Time
+	if r.count > 0 {
+		// occurrences are counted from DTSTART
+		_, err := r.scan(0, wallClock(base.In(r.location)), func(o time.Time) bool {
+			if !o.Before(base) {
+				return true
+			}
+			if !r.excluded(o) {
+				prev = o
+			}
+			return false
+		})
+		if err != nil && err != ErrExhausted {
+			return time.Time{}, err
+		}
+	}
+	// the search begins at UNTIL if it's earlier
+	last := base
+	if !r.until.IsZero() && r.until.Before(base) {
+		last = r.until
+	}
+	for n := r.periodIndex(wallClock(last.In(r.location))); r.count == 0 && n >= 0; n-- {
+		p := r.period(n)
+		if from, _ := r.skip(p); !from.IsZero() {
+			// the periods from the beginning of the skipped range have no occurrences either
+			n = min(n, r.periodIndex(from)+1)
+			continue
+		}
+		occurrences := r.occurrences(p)
+		for i := len(occurrences) - 1; i >= 0; i-- {
+			o := r.local(occurrences[i])
+			if o.After(r.start) && o.Before(base) && (r.until.IsZero() || !o.After(r.until)) && !r.excluded(o) {
+				return o, nil
+			}
+		}
+	}
+	if !prev.IsZero() {
+		return prev, nil
+	}
+	if r.excluded(r.start) {
+		return time.Time{}, ErrExhausted
+	}
+	return r.start, nil
+}
+
+// scan the occurrences after DTSTART of the periods from the n-th on in order until fn returns true, found is
+// false if the periods pass the wall clock horizon. ErrExhausted is returned after UNTIL, or COUNT if n is 0.
+func (r *Recurrence) scan(n int, horizon time.Time, fn func(o time.Time) bool) (bool, error) {
+	counted := 1
+	for {
+		p := r.period(n)
+		if p.After(horizon) {
+			return false, nil
+		}
+		if _, to := r.skip(p); !to.IsZero() {
+			n = r.periodAfter(to)
+			continue
+		}
+		for _, w := range r.occurrences(p) {
+			o := r.local(w)
+			if !o.After(r.start) {
+				continue
+			}
+			if counted++; r.count > 0 && counted > r.count || !r.until.IsZero() && o.After(r.until) {
+				return false, ErrExhausted
+			}
+			if fn(o) {
+				return true, nil
+			}
+		}
+		n++
+	}
+}
+
+// local time of the wall clock w in the location of DTSTART. A wall clock skipped by a DST transition is read
+// with the offset before the transition as RFC 5545 says, so 02:30 in a gap from 02:00 to 03:00 is 03:30.
+func (r *Recurrence) local(w time.Time) time.Time {
+	o := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), 0, r.location)
+	if wallClock(o).Before(w) {
+		// o is read with the offset after the transition and lands before it, in the offset before it
+		_, offset := o.Zone()
+		o = w.Add(-time.Duration(offset) * time.Second).In(r.location)
+	}
+	return o
+}
+
+// period return the wall clock of the beginning of the n-th period
+func (r *Recurrence) period(n int) time.Time {
+	w, k := r.wall, n*r.interval
+	switch r.freq {
+	case yearly:
+		return time.Date(w.Year()+k, 1, 1, 0, 0, 0, 0, time.UTC)
+	case monthly:
+		return time.Date(w.Year(), w.Month()+time.Month(k), 1, 0, 0, 0, 0, time.UTC)
+	case weekly:
+		back := (int(w.Weekday()) - int(r.wkst) + 7) % 7
+		return time.Date(w.Year(), w.Month(), w.Day()-back+7*k, 0, 0, 0, 0, time.UTC)
+	case daily:
+		return time.Date(w.Year(), w.Month(), w.Day()+k, 0, 0, 0, 0, time.UTC)
+	}
+	return w.Truncate(r.unit()).Add(time.Duration(k) * r.unit())
+}
+
+// unit of the sub-daily frequencies
+func (r *Recurrence) unit() time.Duration {
+	switch r.freq {
+	case hourly:
+		return time.Hour
+	case minutely:
+		return time.Minute
+	}
+	return time.Second
+}
+
+// periodIndex of the period containing the wall clock w, 0 if it's before the start
+func (r *Recurrence) periodIndex(w time.Time) int {
+	var units int
+	switch r.freq {
+	case yearly:
+		units = w.Year() - r.wall.Year()
+	case monthly:
+		units = (w.Year()-r.wall.Year())*12 + int(w.Month()) - int(r.wall.Month())
+	case weekly:
+		units = int(w.Sub(r.period(0)).Hours()) / 24 / 7
+	case daily:
+		units = int(w.Sub(r.period(0)).Hours()) / 24
+	default:
+		units = int(w.Sub(r.period(0)) / r.unit())
+	}
+	if units < 0 {
+		return 0
+	}
+	return units / r.interval
+}
+
+// periodAfter return the index of the first period beginning at or after the wall clock w of a sub-daily frequency
+func (r *Recurrence) periodAfter(w time.Time) int {
+	step := r.unit() * time.Duration(r.interval)
+	return int((w.Sub(r.period(0)) + step - 1) / step)
+}
+
+// skip return the wall clock range [from, to) containing the period of a sub-daily frequency beginning at p
+// if it can't have occurrences, zero times otherwise
+func (r *Recurrence) skip(p time.Time) (from, to time.Time) {
+	if r.freq >= daily {
+		return time.Time{}, time.Time{}
+	}
+	day := time.Date(p.Year(), p.Month(), p.Day(), 0, 0, 0, 0, time.UTC)
+	if !r.matchDay(day) {
+		return day, day.AddDate(0, 0, 1)
+	}
+	if r.freq < hourly && len(r.byHour) > 0 && !slices.Contains(r.byHour, p.Hour()) {
+		return p.Truncate(time.Hour), p.Truncate(time.Hour).Add(time.Hour)
+	}
+	if r.freq < minutely && len(r.byMinute) > 0 && !slices.Contains(r.byMinute, p.Minute()) {
+		return p.Truncate(time.Minute), p.Truncate(time.Minute).Add(time.Minute)
+	}
+	return time.Time{}, time.Time{}
+}
+
+// occurrences of the period beginning at p in wall clock, sorted and with BYSETPOS applied
+func (r *Recurrence) occurrences(p time.Time) []time.Time {
+	var days []time.Time
+	switch r.freq {
+	case yearly:
+		days = r.yearDays(p.Year())
+	case monthly:
+		days = r.monthDays(p.Year(), p.Month())
+	case weekly:
+		for i := 0; i < 7; i++ {
+			d := p.AddDate(0, 0, i)
+			if len(r.byMonth) > 0 && !slices.Contains(r.byMonth, int(d.Month())) {
+				continue
+			}
+			if len(r.byDay) == 0 && d.Weekday() == r.wall.Weekday() || r.hasWeekday(d.Weekday()) {
+				days = append(days, d)
+			}
+		}
+	default:
+		// sub-daily periods are already checked by skip
+		if day := time.Date(p.Year(), p.Month(), p.Day(), 0, 0, 0, 0, time.UTC); r.freq < daily || r.matchDay(day) {
+			days = append(days, day)
+		}
+	}
+
+	hours := r.expand(r.byHour, r.wall.Hour(), p.Hour(), hourly)
+	minutes := r.expand(r.byMinute, r.wall.Minute(), p.Minute(), minutely)
+	seconds := r.expand(r.bySecond, r.wall.Second(), p.Second(), secondly)
+	var result []time.Time
+	for _, d := range days {
+		for _, h := range hours {
+			for _, m := range minutes {
+				for _, s := range seconds {
+					result = append(result, time.Date(d.Year(), d.Month(), d.Day(), h, m, s, 0, time.UTC))
+				}
+			}
+		}
+	}
+	return r.setPos(result)
+}
+
+// expand the values of a time field: BY values or the value of DTSTART for coarser frequencies,
+// the value of the period limited by BY values for the frequency of the field and finer ones
+func (r *Recurrence) expand(by []int, start, current int, field frequency) []int {
+	if r.freq > field {
+		if len(by) > 0 {
+			return by
+		}
+		return []int{start}
+	}
+	if len(by) > 0 && !slices.Contains(by, current) {
+		return nil
+	}
+	return []int{current}
+}
+
+// yearDays of FREQ=YEARLY
+func (r *Recurrence) yearDays(year int) []time.Time {
+	switch {
+	case len(r.byMonth) == 0 && len(r.byMonthDay) == 0 && len(r.byDay) == 0:
+		d := time.Date(year, r.wall.Month(), r.wall.Day(), 0, 0, 0, 0, time.UTC)
+		if d.Day() != r.wall.Day() {
+			return nil
+		}
+		return []time.Time{d}
+	case len(r.byMonth) == 0 && len(r.byMonthDay) == 0:
+		// weekdays of the whole year, ordinals count in the year
+		var scope []time.Time
+		for d := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() == year; d = d.AddDate(0, 0, 1) {
+			scope = append(scope, d)
+		}
+		return r.filterWeekdays(scope)
+	}
+	var days []time.Time
+	for m := time.January; m <= time.December; m++ {
+		days = append(days, r.monthDays(year, m)...)
+	}
+	return days
+}
+
+// monthDays of FREQ=MONTHLY, or a month of FREQ=YEARLY
+func (r *Recurrence) monthDays(year int, month time.Month) []time.Time {
+	if len(r.byMonth) > 0 && !slices.Contains(r.byMonth, int(month)) {
+		return nil
+	}
+	var scope []time.Time
+	for d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC); d.Month() == month; d = d.AddDate(0, 0, 1) {
+		scope = append(scope, d)
+	}
+	switch {
+	case len(r.byMonthDay) > 0:
+		var days []time.Time
+		weekdays := r.filterWeekdays(scope)
+		for _, d := range scope {
+			if r.matchMonthDay(d) && (len(r.byDay) == 0 || slices.Contains(weekdays, d)) {
+				days = append(days, d)
+			}
+		}
+		return days
+	case len(r.byDay) > 0:
+		return r.filterWeekdays(scope)
+	}
+	if r.wall.Day() > len(scope) {
+		return nil
+	}
+	return []time.Time{scope[r.wall.Day()-1]}
+}
+
+// filterWeekdays return the days of scope matching BYDAY, ordinals count in scope
+func (r *Recurrence) filterWeekdays(scope []time.Time) []time.Time {
+	var days []time.Time
+	for _, d := range scope {
+		for _, wd := range r.byDay {
+			if d.Weekday() != wd.day {
+				continue
+			}
+			// ordinal of d among the same weekdays in scope, counted from both ends
+			first := int(d.Sub(scope[0]).Hours())/24/7 + 1
+			last := int(scope[len(scope)-1].Sub(d).Hours())/24/7 + 1
+			if wd.n == 0 || wd.n > 0 && first == wd.n || wd.n < 0 && last == -wd.n {
+				days = append(days, d)
+				break
+			}
+		}
+	}
+	return days
+}
+
+// matchDay reports whether the day matches BYMONTH, BYMONTHDAY and BYDAY of daily and finer frequencies
+func (r *Recurrence) matchDay(d time.Time) bool {
+	if len(r.byMonth) > 0 && !slices.Contains(r.byMonth, int(d.Month())) {
+		return false
+	}
+	if len(r.byMonthDay) > 0 && !r.matchMonthDay(d) {
+		return false
+	}
+	return len(r.byDay) == 0 || r.hasWeekday(d.Weekday())
+}
+
+// matchMonthDay reports whether the day matches BYMONTHDAY, negative values count from the end of the month
+func (r *Recurrence) matchMonthDay(d time.Time) bool {
+	days := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, v := range r.byMonthDay {
+		if v == d.Day() || v < 0 && days+v+1 == d.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recurrence) hasWeekday(day time.Weekday) bool {
+	for _, wd := range r.byDay {
+		if wd.day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// setPos select the occurrences of BYSETPOS, negative positions count from the end
+func (r *Recurrence) setPos(occurrences []time.Time) []time.Time {
+	if len(r.bySetPos) == 0 {
+		return occurrences
+	}
+	var selected []time.Time
+	for i, o := range occurrences {
+		for _, pos := range r.bySetPos {
+			if pos == i+1 || pos < 0 && len(occurrences)+pos == i {
+				selected = append(selected, o)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func (r *Recurrence) excluded(t time.Time) bool {
+	for _, ex := range r.exdates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	w := wallClock(t)
+	day := time.Date(w.Year(), w.Month(), w.Day(), 0, 0, 0, 0, time.UTC)
+	return slices.Contains(r.exdays, day)
+}
+
+// parseICalTimes parse the comma separated DATE-TIME or DATE values with the TZID and VALUE parameters
+func parseICalTimes(value string, params string, location *time.Location) ([]time.Time, bool, error) {
+	dateOnly := false
+	for _, param := range strings.Split(params, ";") {
+		k, v, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(k) {
+		case "TZID":
+			l, err := time.LoadLocation(strings.Trim(v, `"`))
+			if err != nil {
+				return nil, false, err
+			}
+			location = l
+		case "VALUE":
+			dateOnly = strings.ToUpper(v) == "DATE"
+		}
+	}
+	var times []time.Time
+	for _, s := range strings.Split(value, ",") {
+		var t time.Time
+		var err error
+		switch {
+		case len(s) == 8:
+			dateOnly = true
+			t, err = time.ParseInLocation("20060102", s, location)
+		case strings.HasSuffix(s, "Z"):
+			t, err = time.ParseInLocation("20060102T150405Z", s, time.UTC)
+		default:
+			t, err = time.ParseInLocation("20060102T150405", s, location)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		times = append(times, t)
+	}
+	return times, dateOnly, nil
+}
+
+// parseInts parse the comma separated values in [min, max], or in [-max, -min] as well if signed
+func parseInts(value string, min, max int, signed bool) ([]int, error) {
+	var values []int
+	for _, s := range strings.Split(value, ",") {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if (v < min || v > max) && !(signed && -v >= min && -v <= max) {
+			return nil, fmt.Errorf("%d out of range [%d, %d]", v, min, max)
+		}
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values, nil
+}
+
+func parseWeekdayNums(value string) ([]weekdayNum, error) {
+	var days []weekdayNum
+	for _, s := range strings.Split(value, ",") {
+		m := weekdayNumPattern.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("invalid weekday %s", s)
+		}
+		wd := weekdayNum{}
+		if m[1] != "" {
+			wd.n, _ = strconv.Atoi(m[1])
+			if wd.n == 0 || wd.n > 53 || wd.n < -53 {
+				return nil, fmt.Errorf("ordinal %d out of range", wd.n)
+			}
+		}
+		for i, name := range rruleWeekdays {
+			if name == m[2] {
+				wd.day = time.Weekday(i)
+			}
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+// wallClock return the wall clock of t in UTC
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
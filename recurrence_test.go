@@ -0,0 +1,299 @@
+package gocronexpr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// occurrences of the schedule from before its start, at most n
+func occurrences(t *testing.T, s Schedule, from time.Time, n int) []string {
+	var result []string
+	base := from
+	for len(result) < n {
+		next, err := s.Next(&base)
+		if errors.Is(err, ErrExhausted) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, next.Format("2006-01-02 15:04"))
+		base = next
+	}
+	return result
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	// examples of RFC 5545 section 3.8.5.3 in America/New_York
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"daily for 10 occurrences", "DTSTART:19970902T090000 RRULE:FREQ=DAILY;COUNT=10", []string{
+			"1997-09-02 09:00", "1997-09-03 09:00", "1997-09-04 09:00", "1997-09-05 09:00", "1997-09-06 09:00",
+			"1997-09-07 09:00", "1997-09-08 09:00", "1997-09-09 09:00", "1997-09-10 09:00", "1997-09-11 09:00"}},
+		{"every 10 days, 5 occurrences", "DTSTART:19970902T090000 RRULE:FREQ=DAILY;INTERVAL=10;COUNT=5", []string{
+			"1997-09-02 09:00", "1997-09-12 09:00", "1997-09-22 09:00", "1997-10-02 09:00", "1997-10-12 09:00"}},
+		{"weekly on Tuesday and Thursday for five weeks",
+			"DTSTART:19970902T090000 RRULE:FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH", []string{
+				"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-09 09:00", "1997-09-11 09:00", "1997-09-16 09:00",
+				"1997-09-18 09:00", "1997-09-23 09:00", "1997-09-25 09:00", "1997-09-30 09:00", "1997-10-02 09:00"}},
+		{"monthly on the first Friday", "DTSTART:19970905T090000 RRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR", []string{
+			"1997-09-05 09:00", "1997-10-03 09:00", "1997-11-07 09:00", "1997-12-05 09:00", "1998-01-02 09:00",
+			"1998-02-06 09:00", "1998-03-06 09:00", "1998-04-03 09:00", "1998-05-01 09:00", "1998-06-05 09:00"}},
+		{"monthly on the second-to-last Monday", "DTSTART:19970922T090000 RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO", []string{
+			"1997-09-22 09:00", "1997-10-20 09:00", "1997-11-17 09:00", "1997-12-22 09:00", "1998-01-19 09:00",
+			"1998-02-16 09:00"}},
+		{"monthly on the third-to-the-last day", "DTSTART:19970928T090000 RRULE:FREQ=MONTHLY;BYMONTHDAY=-3", []string{
+			"1997-09-28 09:00", "1997-10-29 09:00", "1997-11-28 09:00", "1997-12-29 09:00", "1998-01-29 09:00",
+			"1998-02-26 09:00"}},
+		{"every Friday the 13th",
+			"DTSTART:19970902T090000 EXDATE;TZID=America/New_York:19970902T090000 RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			[]string{"1998-02-13 09:00", "1998-03-13 09:00", "1998-11-13 09:00", "1999-08-13 09:00", "2000-10-13 09:00"}},
+		{"last work day of the month", "DTSTART:19970930T090000 RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			[]string{"1997-09-30 09:00", "1997-10-31 09:00", "1997-11-28 09:00", "1997-12-31 09:00", "1998-01-30 09:00",
+				"1998-02-27 09:00", "1998-03-31 09:00"}},
+		{"third instance of Tuesday, Wednesday or Thursday",
+			"DTSTART:19970904T090000 RRULE:FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			[]string{"1997-09-04 09:00", "1997-10-07 09:00", "1997-11-06 09:00"}},
+		{"every 15 minutes for 6 occurrences", "DTSTART:19970902T090000 RRULE:FREQ=MINUTELY;INTERVAL=15;COUNT=6", []string{
+			"1997-09-02 09:00", "1997-09-02 09:15", "1997-09-02 09:30", "1997-09-02 09:45", "1997-09-02 10:00",
+			"1997-09-02 10:15"}},
+		{"every 20 minutes from 9:00 to 16:40",
+			"DTSTART:19970902T090000 RRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10,11,12,13,14,15,16", []string{
+				"1997-09-02 09:00", "1997-09-02 09:20", "1997-09-02 09:40", "1997-09-02 10:00"}},
+		{"every 20 minutes from 16:00, daily", "DTSTART:19970902T160000 RRULE:FREQ=DAILY;BYHOUR=16,9;BYMINUTE=0,20,40",
+			[]string{"1997-09-02 16:00", "1997-09-02 16:20", "1997-09-02 16:40", "1997-09-03 09:00"}},
+		{"yearly in June and July", "DTSTART:19970610T090000 RRULE:FREQ=YEARLY;COUNT=4;BYMONTH=6,7", []string{
+			"1997-06-10 09:00", "1997-07-10 09:00", "1998-06-10 09:00", "1998-07-10 09:00"}},
+		{"every Thursday in March", "DTSTART:19970313T090000 RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH", []string{
+			"1997-03-13 09:00", "1997-03-20 09:00", "1997-03-27 09:00", "1998-03-05 09:00", "1998-03-12 09:00"}},
+		{"US presidential election day",
+			"DTSTART:19961105T090000 RRULE:FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8",
+			[]string{"1996-11-05 09:00", "2000-11-07 09:00", "2004-11-02 09:00"}},
+		{"20th Monday of the year", "DTSTART:19970519T090000 RRULE:FREQ=YEARLY;BYDAY=20MO", []string{
+			"1997-05-19 09:00", "1998-05-18 09:00", "1999-05-17 09:00"}},
+		{"excluded dates", "DTSTART:19970902T090000 RRULE:FREQ=DAILY;COUNT=5 EXDATE;VALUE=DATE:19970903,19970905",
+			[]string{"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-06 09:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRRule(tt.text, newYork)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := time.Date(1990, 1, 1, 0, 0, 0, 0, newYork)
+			got := occurrences(t, r, from, len(tt.want)+1)
+			// rules without COUNT or UNTIL go on
+			if !strings.Contains(tt.text, "COUNT") && !strings.Contains(tt.text, "UNTIL") && len(got) > len(tt.want) {
+				got = got[:len(tt.want)]
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Recurrence.Next() = %v, want %v", got, tt.want)
+			}
+
+			// Prev walks the same occurrences back to DTSTART
+			last, _ := time.ParseInLocation("2006-01-02 15:04", tt.want[len(tt.want)-1], newYork)
+			got = previous(t, r, last.Add(time.Minute))
+			for i, j := 0, len(got)-1; i < j; i, j = i+1, j-1 {
+				got[i], got[j] = got[j], got[i]
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Recurrence.Prev() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// previous occurrences of the schedule before from until it's exhausted
+func previous(t *testing.T, s Schedule, from time.Time) []string {
+	var result []string
+	base := from
+	for {
+		prev, err := s.Prev(&base)
+		if errors.Is(err, ErrExhausted) {
+			return result
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, prev.Format("2006-01-02 15:04"))
+		base = prev
+	}
+}
+
+func TestRecurrence_Next_base(t *testing.T) {
+	r, err := ParseRRule("DTSTART:20200101T090000Z\nRRULE:FREQ=HOURLY;INTERVAL=5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the periods are counted from DTSTART instead of the base
+	base := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := []string{"2020-03-01 14:00", "2020-03-01 19:00", "2020-03-02 00:00"}
+	if got := occurrences(t, r, base, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("Recurrence.Next() = %v, want %v", got, want)
+	}
+}
+
+func TestRecurrence_Prev(t *testing.T) {
+	tests := []struct {
+		text string
+		base time.Time
+		want string
+	}{
+		{"DTSTART:20200101T090000Z RRULE:FREQ=HOURLY;INTERVAL=5", time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC), "2020-03-01 09:00"},
+		// the hours and days without occurrences are skipped
+		{"DTSTART:20200101T090000Z RRULE:FREQ=MINUTELY;BYHOUR=9;BYDAY=MO", time.Date(2020, 3, 4, 8, 0, 0, 0, time.UTC), "2020-03-02 09:59"},
+		{"DTSTART:20200101T090000Z RRULE:FREQ=DAILY;UNTIL=20200110T090000Z", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "2020-01-10 09:00"},
+		{"DTSTART:20200101T090000Z RRULE:FREQ=DAILY;COUNT=3", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "2020-01-03 09:00"},
+		{"DTSTART:20200101T090000Z RRULE:FREQ=DAILY;COUNT=3", time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			r, err := ParseRRule(tt.text, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.Prev(&tt.base)
+			if tt.want == "" {
+				if !errors.Is(err, ErrExhausted) {
+					t.Errorf("Recurrence.Prev() = %v, %v, want %v", got, err, ErrExhausted)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Format("2006-01-02 15:04") != tt.want {
+				t.Errorf("Recurrence.Prev() = %v, want %v", got.Format("2006-01-02 15:04"), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Next_dstGap(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	r, err := ParseRRule("DTSTART;TZID=America/New_York:20240309T023000 RRULE:FREQ=DAILY", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 02:30 of 2024-03-10 is skipped by DST, it's read with the offset of EST
+	want := []string{"2024-03-09 02:30 EST", "2024-03-10 03:30 EDT", "2024-03-11 02:30 EDT"}
+	var got []string
+	for base := time.Date(2024, 3, 1, 0, 0, 0, 0, newYork); len(got) < len(want); {
+		base, err = r.Next(&base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, base.Format("2006-01-02 15:04 MST"))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Recurrence.Next() = %v, want %v", got, want)
+	}
+	base := time.Date(2024, 3, 11, 0, 0, 0, 0, newYork)
+	if prev, _ := r.Prev(&base); prev.Format("2006-01-02 15:04 MST") != want[1] {
+		t.Errorf("Recurrence.Prev() = %v, want %v", prev.Format("2006-01-02 15:04 MST"), want[1])
+	}
+}
+
+// Converting to RRULE and back keeps the fire times
+func TestRecurrence_RRules(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, shanghai)
+	for _, expression := range []string{"0 30 9 * * MON-FRI", "0 0 0 1-7 * MON", "0 0 12 * 6-8 SAT,SUN", "0 0 6,19 * * *",
+		"0 */15 9-17 * * MON-FRI", "0 0 0 25 12 *", "0 0 8 */10 1,7 *"} {
+		t.Run(expression, func(t *testing.T) {
+			c, _ := New(expression, shanghai)
+			rules, err := c.RRules(from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var schedules []*Recurrence
+			for _, rule := range rules {
+				r, err := ParseRRule(rule.String(), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				schedules = append(schedules, r)
+			}
+			base := from
+			for i := 0; i < 50; i++ {
+				want, err := c.Next(&base)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got time.Time
+				for _, r := range schedules {
+					if next, err := r.Next(&base); err == nil && (got.IsZero() || next.Before(got)) {
+						got = next
+					}
+				}
+				if !got.Equal(want) {
+					t.Fatalf("Recurrence.Next(%v) = %v, want %v", base, got, want)
+				}
+				base = want
+			}
+		})
+	}
+}
+
+func TestParseRRule_errors(t *testing.T) {
+	tests := []string{
+		"RRULE:FREQ=DAILY",
+		"DTSTART:20200101T090000",
+		"DTSTART:20200101T090000 RRULE:INTERVAL=2",
+		"DTSTART:20200101T090000 RRULE:FREQ=FORTNIGHTLY",
+		"DTSTART:20200101T090000 RRULE:FREQ=DAILY;INTERVAL=0",
+		"DTSTART:20200101T090000 RRULE:FREQ=DAILY;COUNT=2;UNTIL=20200201T000000Z",
+		"DTSTART:20200101T090000 RRULE:FREQ=WEEKLY;BYMONTHDAY=1",
+		"DTSTART:20200101T090000 RRULE:FREQ=WEEKLY;BYDAY=1MO",
+		"DTSTART:20200101T090000 RRULE:FREQ=MONTHLY;BYMONTHDAY=32",
+		"DTSTART:20200101T090000 RRULE:FREQ=MONTHLY;BYSETPOS=1",
+		"DTSTART:20200101T090000 RRULE:FREQ=YEARLY;BYWEEKNO=20",
+		"DTSTART;TZID=Mars/Olympus:20200101T090000 RRULE:FREQ=DAILY",
+		"DTSTART:20200101T090000 RRULE:FREQ=DAILY RRULE:FREQ=WEEKLY",
+		"DTSTART:20200101T090000 RDATE:20200105T090000 RRULE:FREQ=DAILY",
+	}
+	for _, text := range tests {
+		if _, err := ParseRRule(text, time.UTC); err == nil || !strings.Contains(err.Error(), "rrule") {
+			t.Errorf("ParseRRule(%q) error = %v", text, err)
+		}
+	}
+}
+
+func TestRunSchedule_exhausted(t *testing.T) {
+	start := time.Now().Add(time.Second).Truncate(time.Second)
+	r, err := ParseRRule("DTSTART:"+start.UTC().Format("20060102T150405Z")+" RRULE:FREQ=SECONDLY;COUNT=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executed := 0
+	finished := make(chan struct{})
+	go RunSchedule(context.Background(), r, func(context.Context) error {
+		executed++
+		return nil
+	}, &ScheduleOptions{Finish: func() { close(finished) }})
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunSchedule() didn't finish after the last occurrence")
+	}
+	if executed != 2 {
+		t.Errorf("RunSchedule() executed %d times, want 2", executed)
+	}
+}
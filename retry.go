@@ -0,0 +1,118 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrTimeout is returned for an attempt which does not finish within ScheduleOptions.Timeout
+var ErrTimeout = errors.New("job execution timed out")
+
+// RetryPolicy of failed executions, the backoff doubles after each retry
+type RetryPolicy struct {
+	// MaxAttempts including the first execution, 0 or 1 means no retry
+	MaxAttempts int
+	// InitialBackoff before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff, 0 means no cap
+	MaxBackoff time.Duration
+	// UntilNextFire stop retrying when the next retry would not start before the next scheduled fire
+	UntilNextFire bool
+	// Attempted is called after each attempt, attempt starts with 1
+	Attempted func(attempt int, err error)
+}
+
+// backoff before the retry following the given attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		// doubling stops at the cap, or before it overflows without a cap
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff || d > math.MaxInt64/2 {
+			break
+		}
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// execute fn scheduled at the given time with retry, return the error of the last attempt
+func (s scheduler) execute(ctx context.Context, fn func(ctx context.Context) error, options *ScheduleOptions, scheduled time.Time) error {
+	policy := options.Retry
+	if policy == nil {
+		policy = &RetryPolicy{}
+	}
+	var deadline time.Time
+	if policy.UntilNextFire {
+		if following, err := s.schedule.Next(&scheduled); err == nil {
+			deadline = following
+		}
+	}
+
+	for n := 1; ; n++ {
+		started := time.Now()
+		s.emit(options, Event{Type: EventStarted, Scheduled: scheduled, Started: started, Attempt: n})
+		err := attempt(ctx, fn, options.Timeout)
+		s.emit(options, Event{Type: outcome(err), Scheduled: scheduled, Started: started,
+			Duration: time.Since(started), Attempt: n, Err: err})
+		if policy.Attempted != nil {
+			policy.Attempted(n, err)
+		}
+		if err == nil || n >= policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		backoff := policy.backoff(n)
+		if !deadline.IsZero() && !time.Now().Add(backoff).Before(deadline) {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt run fn once, it returns ErrTimeout without waiting for fn if timeout is reached.
+// A job ignoring its context keeps running in background, but it no longer blocks the schedule.
+func attempt(ctx context.Context, fn func(ctx context.Context) error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return ErrTimeout
+		}
+		return ctx.Err()
+	}
+}
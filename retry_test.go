@@ -0,0 +1,122 @@
+package gocronexpr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	wants := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range wants {
+		if got := p.backoff(i + 1); got != want {
+			t.Errorf("RetryPolicy.backoff(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+
+	// without MaxBackoff the backoff keeps growing but never overflows
+	p = &RetryPolicy{InitialBackoff: time.Second}
+	prev := time.Duration(0)
+	for attempt := 1; attempt <= 200; attempt++ {
+		got := p.backoff(attempt)
+		if got < prev {
+			t.Fatalf("RetryPolicy.backoff(%d) = %v, less than %v", attempt, got, prev)
+		}
+		prev = got
+	}
+}
+
+func Test_scheduler_execute(t *testing.T) {
+	errFail := errors.New("fail")
+	c, err := New("0 0 * * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		failures     int
+		scheduled    time.Time
+		wantErr      error
+		wantAttempts int
+	}{
+		{"no_retry", nil, 1, time.Now(), errFail, 1},
+		{"succeed_after_retry", &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, 2, time.Now(), nil, 3},
+		{"attempts_exhausted", &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, 5, time.Now(), errFail, 3},
+		// the next fire of an hourly schedule at a past hour is already reached
+		{"until_next_fire", &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, UntilNextFire: true}, 5,
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), errFail, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts []int
+			policy := tt.policy
+			if policy != nil {
+				policy.Attempted = func(attempt int, err error) {
+					attempts = append(attempts, attempt)
+				}
+			}
+			calls := 0
+			err := scheduler{c}.execute(context.Background(), func(context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return errFail
+				}
+				return nil
+			}, &ScheduleOptions{Retry: policy}, tt.scheduled)
+			if err != tt.wantErr {
+				t.Errorf("scheduler.execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantAttempts {
+				t.Errorf("scheduler.execute() attempts = %d, want %d", calls, tt.wantAttempts)
+			}
+			if policy != nil && len(attempts) != tt.wantAttempts {
+				t.Errorf("RetryPolicy.Attempted called %d times, want %d", len(attempts), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_attempt_timeout(t *testing.T) {
+	start := time.Now()
+	err := attempt(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, 50*time.Millisecond)
+	if err != ErrTimeout {
+		t.Errorf("attempt() error = %v, wantErr %v", err, ErrTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("attempt() took %v, want about 50ms", elapsed)
+	}
+}
+
+func TestRunSchedule_Timeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	var failures []error
+	RunSchedule(context.Background(), soon(2), func(ctx context.Context) error {
+		// ignore the context to simulate a hung job
+		<-hang
+		return nil
+	}, &ScheduleOptions{
+		Timeout: 10 * time.Millisecond,
+		Failed: func(err error) {
+			failures = append(failures, err)
+		},
+	})
+
+	// the hung job doesn't block the following occurrence
+	if len(failures) != 2 {
+		t.Errorf("failed %d times, want 2", len(failures))
+	}
+	for _, err := range failures {
+		if err != ErrTimeout {
+			t.Errorf("failure = %v, want %v", err, ErrTimeout)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bits-and-blooms/bitset"
+)
+
+// maxRRuleTimes is the most times of day written as one rule each, calendars take the time of day from DTSTART
+const maxRRuleTimes = 24
+
+// rruleWeekdays of RFC 5545 BYDAY starting from Sunday
+var rruleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RRule is a recurrence rule of RFC 5545 with its start
+type RRule struct {
+	// Start is DTSTART, the first occurrence
+	Start time.Time
+	// TZID of Start, empty for UTC and for the floating time of time.Local
+	TZID string
+	// Rule is the value of RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+	Rule string
+}
+
+// String return the DTSTART and RRULE properties separated by CRLF
+func (r RRule) String() string {
+	start := "DTSTART:" + r.Start.Format("20060102T150405")
+	switch {
+	case r.TZID != "":
+		start = "DTSTART;TZID=" + r.TZID + ":" + r.Start.Format("20060102T150405")
+	case r.Start.Location() == time.UTC:
+		start += "Z"
+	}
+	return start + "\r\nRRULE:" + r.Rule
+}
+
+// RRules convert the expression to recurrence rules starting from the first fire at or after from.
+// Each time of day gets its own rule if there are at most 24, otherwise a single rule with BYHOUR, BYMINUTE
+// and BYSECOND is returned. Expressions in time.Local have floating times.
+// ErrNotRepresentable is returned if the location has no IANA name or the expression never fires.
+func (c *CronExpr) RRules(from time.Time) ([]RRule, error) {
+	tzid := c.location.String()
+	switch {
+	case c.location == time.Local, c.location == time.UTC:
+		tzid = ""
+	default:
+		if _, err := time.LoadLocation(tzid); err != nil {
+			return nil, fmt.Errorf("%w: location %s has no IANA name", ErrNotRepresentable, tzid)
+		}
+	}
+
+	times := c.seconds.Count() * c.minutes.Count() * c.hours.Count()
+	if times > maxRRuleTimes {
+		start, err := c.firstAt(from)
+		if err != nil {
+			return nil, err
+		}
+		rule := c.rruleDays() + ";BYHOUR=" + rruleList(c.hours, 0, 23, 0) + ";BYMINUTE=" +
+			rruleList(c.minutes, 0, 59, 0) + ";BYSECOND=" + rruleList(c.seconds, 0, 59, 0)
+		return []RRule{{Start: start, TZID: tzid, Rule: rule}}, nil
+	}
+
+	var rules []RRule
+	for h, ok := c.hours.NextSet(0); ok && h < 24; h, ok = c.hours.NextSet(h + 1) {
+		for m, ok := c.minutes.NextSet(0); ok && m < 60; m, ok = c.minutes.NextSet(m + 1) {
+			for s, ok := c.seconds.NextSet(0); ok && s < 60; s, ok = c.seconds.NextSet(s + 1) {
+				sets := c.sets()
+				sets[0], sets[1], sets[2] = bitset.New(60).Set(s), bitset.New(60).Set(m), bitset.New(24).Set(h)
+				start, err := fromSets(sets, c.location).firstAt(from)
+				if err != nil {
+					return nil, err
+				}
+				rules = append(rules, RRule{Start: start, TZID: tzid, Rule: c.rruleDays()})
+			}
+		}
+	}
+	return rules, nil
+}
+
+// firstAt return the first fire at or after t
+func (c *CronExpr) firstAt(t time.Time) (time.Time, error) {
+	base := t.Add(-time.Second)
+	start, err := c.Next(&base)
+	if err != nil {
+		return start, fmt.Errorf("%w: %w", ErrNotRepresentable, err)
+	}
+	return start, nil
+}
+
+// rruleDays return FREQ and the BY parts of days, the coarsest frequency which keeps them exact is chosen
+func (c *CronExpr) rruleDays() string {
+	allMonths := c.months.Count() == 12
+	allDaysOfMonth := analyze(c.daysOfMonth, 1, 31).kind == patternAll
+	allDaysOfWeek := c.daysOfWeek.Count() == 7
+
+	var parts []string
+	switch {
+	case allDaysOfMonth && allDaysOfWeek:
+		parts = append(parts, "FREQ=DAILY")
+	case allDaysOfMonth && allMonths:
+		parts = append(parts, "FREQ=WEEKLY")
+	case allDaysOfWeek && !allMonths:
+		parts = append(parts, "FREQ=YEARLY")
+	default:
+		parts = append(parts, "FREQ=MONTHLY")
+	}
+	if !allMonths {
+		parts = append(parts, "BYMONTH="+rruleList(c.months, 0, 11, 1))
+	}
+	if !allDaysOfMonth {
+		parts = append(parts, "BYMONTHDAY="+rruleList(c.daysOfMonth, 1, 31, 0))
+	}
+	if !allDaysOfWeek {
+		var days []string
+		for d, ok := c.daysOfWeek.NextSet(0); ok && d < 7; d, ok = c.daysOfWeek.NextSet(d + 1) {
+			days = append(days, rruleWeekdays[d])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// rruleList of the values in [min, max], offset is added to each value
+func rruleList(bits *bitset.BitSet, min, max, offset int) string {
+	var values []string
+	for v := min; v <= max; v++ {
+		if bits.Test(uint(v)) {
+			values = append(values, strconv.Itoa(v+offset))
+		}
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,72 @@
+package gocronexpr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCronExpr_RRules(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	// 2020-01-01 is a Wednesday
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, shanghai)
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"0 0 9 * * *", []string{"DTSTART;TZID=Asia/Shanghai:20200101T090000\r\nRRULE:FREQ=DAILY"}},
+		{"0 30 9 * * MON-FRI", []string{"DTSTART;TZID=Asia/Shanghai:20200101T093000\r\nRRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}},
+		{"0 0 0 1,15 * *", []string{"DTSTART;TZID=Asia/Shanghai:20200101T000000\r\nRRULE:FREQ=MONTHLY;BYMONTHDAY=1,15"}},
+		{"0 0 0 25 12 *", []string{"DTSTART;TZID=Asia/Shanghai:20201225T000000\r\nRRULE:FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25"}},
+		{"0 0 0 13 * FRI", []string{"DTSTART;TZID=Asia/Shanghai:20200313T000000\r\nRRULE:FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR"}},
+		{"0 0 12 * 6-8 SAT,SUN", []string{"DTSTART;TZID=Asia/Shanghai:20200606T120000\r\nRRULE:FREQ=MONTHLY;BYMONTH=6,7,8;BYDAY=SU,SA"}},
+		{"0 0 8 * 1 *", []string{"DTSTART;TZID=Asia/Shanghai:20200101T080000\r\nRRULE:FREQ=DAILY;BYMONTH=1"}},
+		{"0 0 6,19 * * *", []string{
+			"DTSTART;TZID=Asia/Shanghai:20200101T060000\r\nRRULE:FREQ=DAILY",
+			"DTSTART;TZID=Asia/Shanghai:20200101T190000\r\nRRULE:FREQ=DAILY",
+		}},
+		{"0 */15 9-17 * * MON-FRI", []string{
+			"DTSTART;TZID=Asia/Shanghai:20200101T090000\r\nRRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9,10,11,12,13,14,15,16,17;BYMINUTE=0,15,30,45;BYSECOND=0",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, _ := New(tt.expression, shanghai)
+			rules, err := c.RRules(from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range rules {
+				got = append(got, r.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CronExpr.RRules() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronExpr_RRules_location(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, _ := New("0 0 9 * * *", time.UTC)
+	rules, err := c.RRules(from)
+	if err != nil || rules[0].String() != "DTSTART:20200101T090000Z\r\nRRULE:FREQ=DAILY" {
+		t.Errorf("CronExpr.RRules() = %v, %v", rules, err)
+	}
+
+	c, _ = New("0 0 9 * * *", time.Local)
+	rules, err = c.RRules(from)
+	if err != nil || rules[0].TZID != "" {
+		t.Errorf("CronExpr.RRules() = %v, %v, want floating time", rules, err)
+	}
+
+	c, _ = New("0 0 9 * * *", time.FixedZone("X", 3600))
+	if _, err := c.RRules(from); !errors.Is(err, ErrNotRepresentable) {
+		t.Errorf("CronExpr.RRules() error = %v, want ErrNotRepresentable", err)
+	}
+}
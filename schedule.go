@@ -0,0 +1,147 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrExhausted is returned by Next when a schedule with an end has no more fires, RunSchedule finishes on it
+var ErrExhausted = errors.New("schedule has no more fires")
+
+// Schedule of fire times, implemented by CronExpr and the other formats of this package
+type Schedule interface {
+	// Next fire time after t, or after time.Now() if t is nil
+	Next(t *time.Time) (time.Time, error)
+	// Prev fire time before t, or before time.Now() if t is nil
+	Prev(t *time.Time) (time.Time, error)
+	// String return the schedule as written
+	String() string
+}
+
+// scheduler runs jobs on the schedule
+type scheduler struct {
+	schedule Schedule
+}
+
+// RunSchedule run function periodically by the schedule until ctx is done.
+// The context passed to fn is canceled when ctx is done or options.Timeout is reached.
+// Executions run one at a time unless options.SkipOverlap is set.
+func RunSchedule(ctx context.Context, schedule Schedule, fn func(ctx context.Context) error, options *ScheduleOptions) {
+	s := scheduler{schedule}
+	if options == nil {
+		options = &ScheduleOptions{}
+	}
+	base := time.Now()
+	// running is 1 while an execution is in progress in background
+	var running int32
+	var wg sync.WaitGroup
+loop:
+	for {
+		next, err := schedule.Next(&base)
+		if errors.Is(err, ErrExhausted) {
+			break loop
+		}
+		if err != nil {
+			s.logger(options).Error("error get next run time", "error", err)
+			wg.Wait()
+			return
+		}
+
+		// stop after end time
+		if options.End != nil && next.After(*options.End) {
+			break loop
+		}
+		// start after start time
+		if options.Start != nil && next.After(*options.Start) {
+			next = *options.Start
+		}
+		s.emit(options, Event{Type: EventScheduled, Scheduled: next})
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			break loop
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			// the timer and ctx were both ready
+			break loop
+		}
+		base = next
+
+		if options.MisfireThreshold > 0 && time.Since(next) > options.MisfireThreshold {
+			s.emit(options, Event{Type: EventMisfired, Scheduled: next})
+			continue
+		}
+		if options.SkipOverlap && !atomic.CompareAndSwapInt32(&running, 0, 1) {
+			s.emit(options, Event{Type: EventSkipped, Scheduled: next, Err: ErrOverlap})
+			continue
+		}
+		if err := s.acquire(options, next); err != nil {
+			atomic.StoreInt32(&running, 0)
+			s.emit(options, Event{Type: EventSkipped, Scheduled: next, Err: err})
+			continue
+		}
+		if !options.SkipOverlap {
+			s.run(ctx, fn, options, next)
+			continue
+		}
+		wg.Add(1)
+		go func(scheduled time.Time) {
+			defer wg.Done()
+			defer atomic.StoreInt32(&running, 0)
+			s.run(ctx, fn, options, scheduled)
+		}(next)
+	}
+	wg.Wait()
+	if options.Finish != nil {
+		options.Finish()
+	}
+}
+
+// run the occurrence at scheduled and call the hooks of options
+func (s scheduler) run(ctx context.Context, fn func(ctx context.Context) error, options *ScheduleOptions, scheduled time.Time) {
+	if err := s.execute(ctx, fn, options, scheduled); err != nil && options.Failed != nil {
+		options.Failed(err)
+	}
+	if options.Executed != nil {
+		options.Executed()
+	}
+}
+
+// acquire the occurrence at scheduled from options.Locker, return ErrLocked if it's acquired by others
+func (s scheduler) acquire(options *ScheduleOptions, scheduled time.Time) error {
+	if options.Locker == nil {
+		return nil
+	}
+	// unnamed jobs are locked by their schedule, so they don't block unrelated jobs sharing the locker
+	name := options.Name
+	if name == "" {
+		name = s.schedule.String()
+	}
+	locked, err := options.Locker.TryLock(name, scheduled)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return ErrLocked
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package gocronexpr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// everySchedule fires every interval after the given time
+type everySchedule time.Duration
+
+func (s everySchedule) Next(t *time.Time) (time.Time, error) {
+	return t.Add(time.Duration(s)), nil
+}
+
+func (s everySchedule) Prev(t *time.Time) (time.Time, error) {
+	return t.Add(-time.Duration(s)), nil
+}
+
+func (s everySchedule) String() string {
+	return "@every " + time.Duration(s).String()
+}
+
+// listSchedule fires at the listed times in order, it's exhausted after the last one
+type listSchedule []time.Time
+
+func (s listSchedule) Next(t *time.Time) (time.Time, error) {
+	for _, fire := range s {
+		if fire.After(*t) {
+			return fire, nil
+		}
+	}
+	return time.Time{}, ErrExhausted
+}
+
+func (s listSchedule) Prev(t *time.Time) (time.Time, error) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].Before(*t) {
+			return s[i], nil
+		}
+	}
+	return time.Time{}, ErrExhausted
+}
+
+func (s listSchedule) String() string {
+	return fmt.Sprint([]time.Time(s))
+}
+
+// soon return a schedule of n fires a millisecond apart from now
+func soon(n int) listSchedule {
+	now := time.Now()
+	var s listSchedule
+	for i := 1; i <= n; i++ {
+		s = append(s, now.Add(time.Duration(i)*time.Millisecond))
+	}
+	return s
+}
+
+func TestRunSchedule_sequential(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var running, executed, skipped, hooks int32
+	RunSchedule(ctx, everySchedule(time.Millisecond), func(context.Context) error {
+		if atomic.AddInt32(&running, 1) > 1 {
+			t.Error("executions overlap")
+		}
+		// longer than the interval, the following occurrences wait for it
+		time.Sleep(3 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if atomic.AddInt32(&executed, 1) == 5 {
+			cancel()
+		}
+		return nil
+	}, &ScheduleOptions{
+		Executed: func() {
+			atomic.AddInt32(&hooks, 1)
+		},
+		Observer: ObserverFunc(func(event Event) {
+			if event.Type == EventSkipped {
+				atomic.AddInt32(&skipped, 1)
+			}
+		}),
+	})
+
+	if executed != 5 || hooks != 5 || skipped != 0 {
+		t.Errorf("executed %d, Executed called %d, skipped %d, want 5, 5 and 0", executed, hooks, skipped)
+	}
+}
+
+func TestRunSchedule_SkipOverlap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	skipped := make(chan struct{})
+	var once sync.Once
+	var mu sync.Mutex
+	counts := make(map[EventType]int)
+	RunSchedule(ctx, everySchedule(time.Millisecond), func(context.Context) error {
+		// the schedule goes on while the execution is blocked
+		<-skipped
+		cancel()
+		return nil
+	}, &ScheduleOptions{
+		SkipOverlap: true,
+		Observer: ObserverFunc(func(event Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			counts[event.Type]++
+			if event.Type == EventSkipped {
+				if event.Err != ErrOverlap {
+					t.Errorf("Event.Err = %v, want %v", event.Err, ErrOverlap)
+				}
+				once.Do(func() { close(skipped) })
+			}
+		}),
+	})
+
+	if counts[EventStarted] != 1 || counts[EventSucceeded] != 1 || counts[EventSkipped] < 1 {
+		t.Errorf("started %d, succeeded %d, skipped %d, want 1, 1 and at least 1",
+			counts[EventStarted], counts[EventSucceeded], counts[EventSkipped])
+	}
+}
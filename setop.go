@@ -0,0 +1,140 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bits-and-blooms/bitset"
+	"time"
+)
+
+// ErrNotRepresentable is returned when the result of a set operation is not a single cron expression
+var ErrNotRepresentable = errors.New("result is not representable as a single cron expression")
+
+// fieldNames and fieldBounds in the order of the expression
+var (
+	fieldNames  = [6]string{"second", "minute", "hour", "day of month", "month", "day of week"}
+	fieldBounds = [6][2]int{{0, 59}, {0, 59}, {0, 23}, {1, 31}, {0, 11}, {0, 6}}
+)
+
+// sets of the fields in the order of the expression
+func (c *CronExpr) sets() [6]*bitset.BitSet {
+	return [6]*bitset.BitSet{c.seconds, c.minutes, c.hours, c.daysOfMonth, c.months, c.daysOfWeek}
+}
+
+// Equal reports whether both expressions have the same fields and location, regardless of how they are written
+func (c *CronExpr) Equal(other *CronExpr) bool {
+	if other == nil || !sameLocation(c.location, other.location) {
+		return false
+	}
+	a, b := c.sets(), other.sets()
+	for i := range a {
+		if !sameBits(a[i], b[i], i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersect return the expression firing when both expressions fire.
+// ErrNotRepresentable is returned if the locations differ or they never fire at the same time, an error if other is nil.
+// The error also wraps ErrNeverFires if each field has common values but the days never occur.
+func (c *CronExpr) Intersect(other *CronExpr) (*CronExpr, error) {
+	if other == nil {
+		return nil, fmt.Errorf("intersect of \"%s\" and nil expression", c.expression)
+	}
+	if !sameLocation(c.location, other.location) {
+		return nil, fmt.Errorf("%w: different locations %s and %s", ErrNotRepresentable, c.location, other.location)
+	}
+	a, b := c.sets(), other.sets()
+	var result [6]*bitset.BitSet
+	for i := range a {
+		result[i] = a[i].Intersection(b[i])
+		if !result[i].Any() {
+			return nil, fmt.Errorf("%w: no common %s of \"%s\" and \"%s\"", ErrNotRepresentable, fieldNames[i], c.expression, other.expression)
+		}
+	}
+	intersection := fromSets(result, c.location)
+	if err := intersection.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotRepresentable, err)
+	}
+	return intersection, nil
+}
+
+// Union return the expression firing when either expression fires.
+// It's representable if one expression contains the other, or they differ in only one field, other must not be nil.
+func (c *CronExpr) Union(other *CronExpr) (*CronExpr, error) {
+	if other == nil {
+		return nil, fmt.Errorf("union of \"%s\" and nil expression", c.expression)
+	}
+	if !sameLocation(c.location, other.location) {
+		return nil, fmt.Errorf("%w: different locations %s and %s", ErrNotRepresentable, c.location, other.location)
+	}
+	a, b := c.sets(), other.sets()
+	aContainsB, bContainsA := true, true
+	differ := 0
+	for i := range a {
+		superset, subset := a[i].IsSuperSet(b[i]), b[i].IsSuperSet(a[i])
+		aContainsB = aContainsB && superset
+		bContainsA = bContainsA && subset
+		if !superset || !subset {
+			differ++
+		}
+	}
+	if !aContainsB && !bContainsA && differ > 1 {
+		return nil, fmt.Errorf("%w: \"%s\" and \"%s\" differ in %d fields", ErrNotRepresentable, c.expression, other.expression, differ)
+	}
+
+	var result [6]*bitset.BitSet
+	for i := range a {
+		result[i] = a[i].Union(b[i])
+	}
+	if aContainsB {
+		result = a
+	} else if bContainsA {
+		result = b
+	}
+	return fromSets(result, c.location), nil
+}
+
+// fromSets create an expression of the given fields, its expression is the canonical form
+func fromSets(sets [6]*bitset.BitSet, location *time.Location) *CronExpr {
+	c := &CronExpr{
+		location:    location,
+		seconds:     sets[0].Clone(),
+		minutes:     sets[1].Clone(),
+		hours:       sets[2].Clone(),
+		daysOfMonth: sets[3].Clone(),
+		months:      sets[4].Clone(),
+		daysOfWeek:  sets[5].Clone(),
+	}
+	c.expression = c.Canonical(false)
+	return c
+}
+
+// sameBits compare the values of the i-th field, bitsets may have different lengths
+func sameBits(a, b *bitset.BitSet, i int) bool {
+	for v := fieldBounds[i][0]; v <= fieldBounds[i][1]; v++ {
+		if a.Test(uint(v)) != b.Test(uint(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameLocation(a, b *time.Location) bool {
+	return a == b || a != nil && b != nil && a.String() == b.String()
+}
@@ -0,0 +1,115 @@
+package gocronexpr
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronExpr_Equal(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	shanghai2, _ := time.LoadLocation("Asia/Shanghai")
+
+	tests := []struct {
+		a, b       string
+		aLoc, bLoc *time.Location
+		want       bool
+	}{
+		{"0 0 * * * mon,tue,wed", "0 0 * * * 1-3", time.UTC, time.UTC, true},
+		{"0 0 12 * * 0", "0 0 12 ? * 7", time.UTC, time.UTC, true},
+		{"0 */15 * * * *", "0 0,15,30,45 * * * *", shanghai, shanghai2, true},
+		{"0 0 * * * *", "0 0 * * * *", time.UTC, shanghai, false},
+		{"0 0 * * * 1-3", "0 0 * * * 1-4", time.UTC, time.UTC, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, _ := New(tt.a, tt.aLoc)
+			b, _ := New(tt.b, tt.bLoc)
+			if got := a.Equal(b); got != tt.want {
+				t.Errorf("CronExpr.Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronExpr_Intersect(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    string
+		wantErr bool
+	}{
+		{"0 */15 * * * *", "0 */10 * * * *", "0 */30 * * * *", false},
+		{"0 0 8-18 * * MON-FRI", "0 0 12-23 * * *", "0 0 12-18 * * 1-5", false},
+		{"0 0 8 * * MON", "0 0 9 * * MON", "", true},
+		{"0 0 0 31 * *", "0 0 0 * FEB *", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, _ := New(tt.a, time.UTC)
+			b, _ := New(tt.b, time.UTC)
+			got, err := a.Intersect(b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CronExpr.Intersect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrNotRepresentable) {
+					t.Errorf("CronExpr.Intersect() error = %v, want ErrNotRepresentable", err)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("CronExpr.Intersect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	a, _ := New("0 0 8 * * MON", time.UTC)
+	if got, err := a.Intersect(nil); got != nil || err == nil {
+		t.Errorf("CronExpr.Intersect(nil) = %v, %v, want error", got, err)
+	}
+}
+
+func TestCronExpr_Union(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    string
+		wantErr bool
+	}{
+		{"0 0 8 * * MON", "0 0 9 * * MON", "0 0 8-9 * * 1", false},
+		{"0 0 8 * * MON", "0 0 8 * * TUE", "0 0 8 * * 1-2", false},
+		{"0 0 * * * *", "0 0 8 * * MON", "0 0 * * * *", false},
+		{"0 0 8 * * MON", "0 0 * * * *", "0 0 * * * *", false},
+		{"0 0 8 * * MON", "0 0 9 * * TUE", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, _ := New(tt.a, time.UTC)
+			b, _ := New(tt.b, time.UTC)
+			got, err := a.Union(b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CronExpr.Union() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrNotRepresentable) {
+					t.Errorf("CronExpr.Union() error = %v, want ErrNotRepresentable", err)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("CronExpr.Union() = %v, want %v", got, tt.want)
+			}
+			// the result is a usable expression
+			if _, err := got.Next(nil); err != nil {
+				t.Errorf("CronExpr.Next() error = %v", err)
+			}
+		})
+	}
+
+	a, _ := New("0 0 8 * * MON", time.UTC)
+	if got, err := a.Union(nil); got != nil || err == nil {
+		t.Errorf("CronExpr.Union(nil) = %v, %v, want error", got, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements sql.Scanner, the column is in the text form of MarshalText and parse errors are returned
+func (c *CronExpr) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(v))
+	case []byte:
+		return c.UnmarshalText(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into CronExpr, use NullCronExpr")
+	}
+	return fmt.Errorf("cannot scan %T into CronExpr", src)
+}
+
+// Value implements driver.Valuer, the value is the text form of MarshalText
+func (c CronExpr) Value() (driver.Value, error) {
+	text, err := c.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// NullCronExpr is a CronExpr that may be NULL, like sql.NullString
+type NullCronExpr struct {
+	CronExpr CronExpr
+	Valid    bool // Valid is true if CronExpr is not NULL
+}
+
+// Scan implements sql.Scanner
+func (n *NullCronExpr) Scan(src interface{}) error {
+	if src == nil {
+		n.CronExpr, n.Valid = CronExpr{}, false
+		return nil
+	}
+	if err := n.CronExpr.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer
+func (n NullCronExpr) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.CronExpr.Value()
+}
@@ -0,0 +1,74 @@
+package gocronexpr
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestCronExpr_Scan(t *testing.T) {
+	tests := []struct {
+		src     interface{}
+		want    string
+		wantErr bool
+	}{
+		{"TZ=UTC 0 0 9 * * *", "TZ=UTC 0 0 9 * * *", false},
+		{[]byte("TZ=UTC */5 * * * * *"), "TZ=UTC */5 * * * * *", false},
+		{"0 0 0 31 4 *", "", true},
+		{"0 0 *", "", true},
+		{nil, "", true},
+		{42, "", true},
+	}
+	for _, tt := range tests {
+		var c CronExpr
+		err := c.Scan(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CronExpr.Scan(%v) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got, _ := c.Value(); got != tt.want {
+			t.Errorf("CronExpr.Value() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestCronExpr_Value(t *testing.T) {
+	c, err := New("0 0 9 * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// database/sql converts arguments like this, a CronExpr passed by value must be a driver.Valuer
+	for _, arg := range []interface{}{*c, c} {
+		if v, err := driver.DefaultParameterConverter.ConvertValue(arg); err != nil || v != "TZ=UTC 0 0 9 * * *" {
+			t.Errorf("ConvertValue(%T) = %v, %v, want TZ=UTC 0 0 9 * * *", arg, v, err)
+		}
+	}
+}
+
+func TestNullCronExpr(t *testing.T) {
+	var n NullCronExpr
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("NullCronExpr.Scan(nil) = %v, %v", n.Valid, err)
+	}
+	if v, err := n.Value(); v != nil || err != nil {
+		t.Errorf("NullCronExpr.Value() = %v, %v, want nil", v, err)
+	}
+
+	if err := n.Scan("TZ=UTC 0 0 * * * *"); err != nil || !n.Valid {
+		t.Fatalf("NullCronExpr.Scan() = %v, %v", n.Valid, err)
+	}
+	base := time.Date(2020, 1, 1, 0, 30, 0, 0, time.UTC)
+	if next, _ := n.CronExpr.Next(&base); !next.Equal(base.Add(30 * time.Minute)) {
+		t.Errorf("Next() = %v", next)
+	}
+	if v, _ := n.Value(); v != "TZ=UTC 0 0 * * * *" {
+		t.Errorf("NullCronExpr.Value() = %v", v)
+	}
+
+	if err := n.Scan("0 0 0 30 2 *"); err == nil || n.Valid {
+		t.Errorf("NullCronExpr.Scan() = %v, %v, want error", n.Valid, err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"time"
+)
+
+// Stats of the fires in a period
+type Stats struct {
+	// From and To is the period, both inclusive
+	From time.Time
+	To   time.Time
+	// Count of fires, First and Last are zero if it never fires
+	Count int
+	First time.Time
+	Last  time.Time
+	// MinInterval, MaxInterval and MeanInterval between consecutive fires, zero if it fires less than twice
+	MinInterval  time.Duration
+	MaxInterval  time.Duration
+	MeanInterval time.Duration
+	// ByHour and ByWeekday count fires by the hour of day and day of week in the location of the expression
+	ByHour    [24]int
+	ByWeekday [7]int
+}
+
+// Stats enumerate the fires between from and to, both inclusive
+func (c *CronExpr) Stats(from, to time.Time) (Stats, error) {
+	stats := Stats{From: from, To: to}
+	base := from.Add(-time.Second)
+	for {
+		next, err := c.Next(&base)
+		if err != nil {
+			return stats, err
+		}
+		if next.After(to) {
+			break
+		}
+		if stats.Count > 0 {
+			interval := next.Sub(stats.Last)
+			if stats.Count == 1 || interval < stats.MinInterval {
+				stats.MinInterval = interval
+			}
+			if interval > stats.MaxInterval {
+				stats.MaxInterval = interval
+			}
+		} else {
+			stats.First = next
+		}
+		stats.Count++
+		stats.Last = next
+		stats.ByHour[next.Hour()]++
+		stats.ByWeekday[next.Weekday()]++
+		base = next
+	}
+	if stats.Count > 1 {
+		stats.MeanInterval = stats.Last.Sub(stats.First) / time.Duration(stats.Count-1)
+	}
+	return stats, nil
+}
+
+// Rate is the average number of fires per period, e.g. Rate(24 * time.Hour) is the fires per day
+func (s Stats) Rate(period time.Duration) float64 {
+	span := s.To.Sub(s.From)
+	if span <= 0 {
+		return 0
+	}
+	return float64(s.Count) * float64(period) / float64(span)
+}
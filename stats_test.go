@@ -0,0 +1,65 @@
+package gocronexpr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronExpr_Stats(t *testing.T) {
+	// 2020-01-06 is a Monday
+	from := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	week := from.Add(7*24*time.Hour - time.Second)
+	tests := []struct {
+		expression string
+		from, to   time.Time
+		count      int
+		min, max   time.Duration
+		mean       time.Duration
+	}{
+		{"0 0 * * * *", from, week, 168, time.Hour, time.Hour, time.Hour},
+		{"0 0 9-17 * * MON-FRI", from, week, 45, time.Hour, 16 * time.Hour, 104 * time.Hour / 44},
+		{"0 0 0 1 1 *", from, week, 0, 0, 0, 0},
+		{"0 0 12 * * *", from.Add(12 * time.Hour), from.Add(12 * time.Hour), 1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, _ := New(tt.expression, time.UTC)
+			got, err := c.Stats(tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Count != tt.count || got.MinInterval != tt.min || got.MaxInterval != tt.max || got.MeanInterval != tt.mean {
+				t.Errorf("CronExpr.Stats() = %d %v %v %v, want %d %v %v %v", got.Count, got.MinInterval, got.MaxInterval,
+					got.MeanInterval, tt.count, tt.min, tt.max, tt.mean)
+			}
+			hours, weekdays := 0, 0
+			for _, n := range got.ByHour {
+				hours += n
+			}
+			for _, n := range got.ByWeekday {
+				weekdays += n
+			}
+			if hours != got.Count || weekdays != got.Count {
+				t.Errorf("CronExpr.Stats() histograms sum to %d and %d, want %d", hours, weekdays, got.Count)
+			}
+		})
+	}
+}
+
+func TestCronExpr_Stats_histograms(t *testing.T) {
+	from := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	c, _ := New("0 0 9-17 * * MON-FRI", time.UTC)
+	got, err := c.Stats(from, from.Add(7*24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ByHour[8] != 0 || got.ByHour[9] != 5 || got.ByHour[17] != 5 {
+		t.Errorf("CronExpr.Stats() ByHour = %v", got.ByHour)
+	}
+	if got.ByWeekday[time.Sunday] != 0 || got.ByWeekday[time.Monday] != 9 || got.ByWeekday[time.Saturday] != 0 {
+		t.Errorf("CronExpr.Stats() ByWeekday = %v", got.ByWeekday)
+	}
+	if rate := got.Rate(24 * time.Hour); rate < 6.42 || rate > 6.43 {
+		t.Errorf("Stats.Rate() = %v, want 45 per week", rate)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 dongfg
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gocronexpr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNeverFires is returned when the expression is valid but no time matches it
+var ErrNeverFires = errors.New("cron expression never fires")
+
+// maxGapYears between two fires of an expression passing validate, which Next and Prev search at most.
+// A February 29 falls on the same day of week again after up to 40 years, as 2100 is not a leap year.
+const maxGapYears = 40
+
+// daysIn is the maximum days of each month, February has 29 days in leap years
+var daysIn = [12]int{31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// validate check the days of month, months and days of week can meet
+func (c *CronExpr) validate() error {
+	var days []int
+	for v, ok := c.daysOfMonth.NextSet(1); ok; v, ok = c.daysOfMonth.NextSet(v + 1) {
+		days = append(days, int(v))
+	}
+	var months []string
+	exists, found := false, false
+	for m, ok := c.months.NextSet(0); ok && m < 12; m, ok = c.months.NextSet(m + 1) {
+		months = append(months, monthNames[m])
+		for _, day := range days {
+			if day <= daysIn[m] {
+				exists = true
+				found = found || c.onWeekday(int(m), day)
+			}
+		}
+	}
+	if found {
+		return nil
+	}
+
+	list := make([]string, len(days))
+	for i, day := range days {
+		list[i] = strconv.Itoa(day)
+	}
+	if !exists {
+		return fmt.Errorf("%w: day %s never occurs in %s in expression \"%s\"",
+			ErrNeverFires, strings.Join(list, ","), strings.Join(months, ","), c.expression)
+	}
+	return fmt.Errorf("%w: day %s of %s never falls on the days of week in expression \"%s\"",
+		ErrNeverFires, strings.Join(list, ","), strings.Join(months, ","), c.expression)
+}
+
+// onWeekday report whether the day of month falls on one of the days of week in the 400 years Gregorian cycle
+func (c *CronExpr) onWeekday(month, day int) bool {
+	for year := 2000; year < 2400; year++ {
+		t := time.Date(year, time.Month(month+1), day, 0, 0, 0, 0, time.UTC)
+		if t.Day() == day && c.daysOfWeek.Test(uint(t.Weekday())) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package gocronexpr
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNew_neverFires(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{"0 0 0 30 2 *", true},
+		{"0 0 0 31 4 *", true},
+		{"0 0 0 31 2,4,6,9,11 *", true},
+		{"0 0 0 30-31 FEB *", true},
+		{"0 0 0 29 2 *", false},
+		{"0 0 0 31 4,5 *", false},
+		{"0 0 0 13 * FRI", false},
+		{"0 0 0 29 2 MON", false},
+		{"0 0 0 * * *", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := New(tt.expression, time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrNeverFires) {
+				t.Errorf("New() error = %v, want ErrNeverFires", err)
+			}
+		})
+	}
+}
+
+// The fires of valid expressions are found however far apart they are
+func TestCronExpr_rareDays(t *testing.T) {
+	tests := []struct {
+		expression string
+		base       time.Time
+		wantNext   time.Time
+		wantPrev   time.Time
+	}{
+		{"0 0 0 29 2 MON", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2044, 2, 29, 0, 0, 0, 0, time.UTC), time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// 2100 is not a leap year
+		{"0 0 0 29 2 SUN", time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2128, 2, 29, 0, 0, 0, 0, time.UTC), time.Date(2088, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 0 13 1 FRI", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2034, 1, 13, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 13, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			c, err := New(tt.expression, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := c.Next(&tt.base); err != nil || !got.Equal(tt.wantNext) {
+				t.Errorf("CronExpr.Next() = %v, %v, want %v", got, err, tt.wantNext)
+			}
+			if got, err := c.Prev(&tt.base); err != nil || !got.Equal(tt.wantPrev) {
+				t.Errorf("CronExpr.Prev() = %v, %v, want %v", got, err, tt.wantPrev)
+			}
+			if stats, err := c.Stats(tt.base, tt.base.AddDate(1, 0, 0)); err != nil || stats.Count != 0 {
+				t.Errorf("CronExpr.Stats() = %v, %v, want no fires", stats.Count, err)
+			}
+		})
+	}
+}